@@ -49,14 +49,14 @@ func main() {
 		return
 	}
 
-	structs := []interface{}{
-		&ex.User{},
-		ex.Entry{},
-		ex.PlayerComment{},
-		ex.Bookmark{},
-	}
+	// ex.Schema() is generated by go generate
+	dm.AddStruct(ex.Schema()...)
 
-	dm.AddStruct(structs...)
+	err = dm.Validate()
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
 
 	err = dm.Generate()
 	if err != nil {
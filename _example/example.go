@@ -1,5 +1,7 @@
 package example
 
+//go:generate ddl-maker gen
+
 import (
 	"database/sql"
 	"time"
@@ -102,7 +104,7 @@ func (b Bookmark) Indexes() dialect.Indexes {
 func (b Bookmark) ForeignKeys() dialect.ForeignKeys {
 	return dialect.ForeignKeys{
 		mysql.AddForeignKey(
-			[]string{"player_id"},
+			[]string{"user_id"},
 			[]string{"id"},
 			"player",
 		),
@@ -55,7 +55,7 @@ CREATE TABLE `bookmark` (
     `updated_at` DATETIME NOT NULL,
     UNIQUE `user_id_entry_id` (`user_id`, `entry_id`),
     FOREIGN KEY (`entry_id`) REFERENCES `entry` (`id`),
-    FOREIGN KEY (`player_id`) REFERENCES `player` (`id`),
+    FOREIGN KEY (`user_id`) REFERENCES `player` (`id`),
     PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4;
 
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	ddlmaker "github.com/kayac/ddl-maker"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// config is the config file of ddl-maker, which is YAML or TOML by its extension
+type config struct {
+	Driver  string `yaml:"driver" toml:"driver"`
+	Engine  string `yaml:"engine" toml:"engine"`
+	Charset string `yaml:"charset" toml:"charset"`
+	// Output is the file written by generate and diff, which is stdout if empty or "-"
+	Output string `yaml:"output" toml:"output"`
+	// Packages are the package patterns loaded by AddPackages, such as "./models/..."
+	Packages []string `yaml:"packages" toml:"packages"`
+	// Tables overrides the tables by their names
+	Tables map[string]tableConfig `yaml:"tables" toml:"tables"`
+}
+
+// tableConfig overrides a table
+type tableConfig struct {
+	Skip    bool              `yaml:"skip" toml:"skip"`
+	Comment string            `yaml:"comment" toml:"comment"`
+	Options map[string]string `yaml:"options" toml:"options"`
+}
+
+func loadConfig(path string) (config, error) {
+	var conf config
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return conf, errors.Wrap(err, "error read config")
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yml", ".yaml":
+		err = yaml.UnmarshalStrict(b, &conf)
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(b), &conf)
+		if err == nil && len(md.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown keys %v", md.Undecoded())
+		}
+	default:
+		return conf, fmt.Errorf("error config %s: unknown extension %s", path, ext)
+	}
+	if err != nil {
+		return conf, errors.Wrapf(err, "error parse config %s", path)
+	}
+
+	if conf.Driver == "" {
+		return conf, fmt.Errorf("error config %s: driver is required", path)
+	}
+	if len(conf.Packages) == 0 {
+		return conf, fmt.Errorf("error config %s: packages are required", path)
+	}
+
+	return conf, nil
+}
+
+// makerConfig returns the config of ddlmaker.New
+func (conf config) makerConfig() ddlmaker.Config {
+	tables := make(map[string]ddlmaker.TableConfig, len(conf.Tables))
+	for name, t := range conf.Tables {
+		tables[name] = ddlmaker.TableConfig{
+			Skip:    t.Skip,
+			Comment: t.Comment,
+			Options: t.Options,
+		}
+	}
+
+	return ddlmaker.Config{
+		OutFilePath: conf.Output,
+		DB: ddlmaker.DBConfig{
+			Driver:  conf.Driver,
+			Engine:  conf.Engine,
+			Charset: conf.Charset,
+		},
+		Tables: tables,
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/kayac/ddl-maker/cmd/ddl-maker
+
+go 1.22.0
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/kayac/ddl-maker v0.2.0
+	github.com/kayac/ddl-maker/static v0.1.0
+	github.com/pkg/errors v0.9.1
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/serenize/snaker v0.0.0-20171204205717-a683aaf2d516 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/tools v0.30.0 // indirect
+)
@@ -0,0 +1,180 @@
+// Command ddl-maker generates ddl from the structs of Go packages by a config file.
+//
+//	ddl-maker [-config ddl-maker.yml] generate [-o file]
+//	ddl-maker [-config ddl-maker.yml] diff [-o file] <ddl file>
+//	ddl-maker [-config ddl-maker.yml] validate
+//	ddl-maker [-config ddl-maker.yml] docs [-o file]
+//	ddl-maker gen [-o file] [dir]
+//
+// gen is for go generate, and writes zz_ddl.go which declares func Schema() []interface{} of the package in dir.
+//
+// It exits with 1 on failure, and 2 on invalid arguments.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	ddlmaker "github.com/kayac/ddl-maker"
+	"github.com/kayac/ddl-maker/static"
+	"github.com/pkg/errors"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+)
+
+const usage = `Usage: ddl-maker [-config file] <command> [arguments]
+
+Commands:
+  generate [-o file]         write CREATE TABLE statements of the packages
+  diff [-o file] <ddl file>  write ALTER TABLE statements from the tables of the ddl file
+  validate                   check the keys and indexes of the tables
+  docs [-o file]             write a Markdown document of the tables
+  gen [-o file] [dir]        write zz_ddl.go of the package in dir without the config, for go generate
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ddl-maker", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "ddl-maker.yml", "config file, which is YAML or TOML")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	cmdFlags := flag.NewFlagSet(command, flag.ContinueOnError)
+	cmdFlags.SetOutput(stderr)
+	var output *string
+	switch command {
+	case "generate", "diff":
+		output = cmdFlags.String("o", "", "output file, which is the output of the config if empty, or stdout if -")
+	case "docs":
+		output = cmdFlags.String("o", "-", "output file, or stdout if -")
+	case "gen":
+		output = cmdFlags.String("o", "", "output file, which is zz_ddl.go in dir if empty, or stdout if -")
+	case "validate":
+	default:
+		fmt.Fprintf(stderr, "unknown command %s\n", command)
+		flags.Usage()
+		return exitUsage
+	}
+	if err := cmdFlags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if command == "diff" && cmdFlags.NArg() != 1 {
+		fmt.Fprintln(stderr, "diff requires a ddl file")
+		return exitUsage
+	}
+	if command == "gen" {
+		return gen(cmdFlags.Args(), *output, stdout, stderr)
+	}
+
+	conf, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	dm, err := ddlmaker.New(conf.makerConfig())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if err := static.AddPackages(dm, conf.Packages...); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	var buf bytes.Buffer
+	switch command {
+	case "generate":
+		err = dm.GenerateTo(&buf)
+	case "diff":
+		err = dm.GenerateDiffFromFileTo(&buf, cmdFlags.Arg(0))
+	case "docs":
+		err = dm.GenerateDocTo(&buf)
+	case "validate":
+		err = dm.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if output == nil {
+		return exitOK
+	}
+
+	path := *output
+	if path == "" {
+		path = conf.Output
+	}
+	if err := writeOutput(path, buf.Bytes(), stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// gen writes the schema of the package in the directory of args, which is the current directory if empty
+func gen(args []string, output string, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		fmt.Fprintln(stderr, "gen accepts a directory")
+		return exitUsage
+	}
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+	if output == "" {
+		output = filepath.Join(dir, static.SCHEMAFILENAME)
+	}
+
+	var buf bytes.Buffer
+	if err := static.GenerateSchemaTo(&buf, dir); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if err := writeOutput(output, buf.Bytes(), stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// writeOutput writes b to the file of path, or stdout if path is empty or "-".
+// The file is written only after the ddl is generated, so that it is not broken on failure.
+func writeOutput(path string, b []byte, stdout io.Writer) error {
+	if path == "" || path == "-" {
+		_, err := stdout.Write(b)
+		return errors.Wrap(err, "error write output")
+	}
+
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return errors.Wrap(err, "error write output")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const yamlConfig = `driver: mysql
+engine: InnoDB
+charset: utf8mb4
+output: %s
+packages:
+  - ./testdata/models
+tables:
+  author:
+    comment: writers
+    options:
+      ROW_FORMAT: DYNAMIC
+`
+
+const tomlConfig = `driver = "mysql"
+engine = "InnoDB"
+charset = "utf8mb4"
+packages = ["./testdata/models"]
+
+[tables.author]
+skip = true
+`
+
+func writeConfig(t *testing.T, name, config string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal("error write config", err)
+	}
+
+	return path
+}
+
+func TestRun(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "schema.sql")
+	config := writeConfig(t, "ddl-maker.yml", strings.Replace(yamlConfig, "%s", output, 1))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-config", config, "generate"}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error generate", code, stderr.String())
+	}
+	ddl, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal("error read output", err)
+	}
+	if !strings.Contains(string(ddl), "ROW_FORMAT=DYNAMIC COMMENT='writers';") {
+		t.Fatal("error generate with table config", string(ddl))
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-config", config, "diff", "-o", "-", output}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error diff", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "CREATE TABLE") {
+		t.Fatal("error diff against the generated ddl", stdout.String())
+	}
+
+	if code := run([]string{"-config", config, "validate"}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error validate", code, stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-config", config, "docs"}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error docs", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "## `author`\n\nwriters\n") {
+		t.Fatal("error docs", stdout.String())
+	}
+}
+
+func TestRunTOML(t *testing.T) {
+	config := writeConfig(t, "ddl-maker.toml", tomlConfig)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-config", config, "generate"}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error generate", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "CREATE TABLE `author`") || !strings.Contains(stdout.String(), "CREATE TABLE `blog_entry`") {
+		t.Fatal("error generate to stdout", stdout.String())
+	}
+}
+
+func TestRunGen(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "zz_ddl.go")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"gen", "-o", output, "./testdata/models"}, &stdout, &stderr); code != exitOK {
+		t.Fatal("error gen", code, stderr.String())
+	}
+	src, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal("error read output", err)
+	}
+	if !strings.Contains(string(src), "package models\n") || !strings.Contains(string(src), "func Schema() []interface{} {") {
+		t.Fatal("error gen", string(src))
+	}
+}
+
+func TestRunError(t *testing.T) {
+	config := writeConfig(t, "ddl-maker.yml", "driver: mysql\npackages: [./testdata/invalid]\n")
+	unknownKey := writeConfig(t, "ddl-maker.yml", "driver: mysql\npackage: [./testdata/models]\n")
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"-config", config}, exitUsage},
+		{[]string{"-config", config, "unknown"}, exitUsage},
+		{[]string{"-config", config, "diff"}, exitUsage},
+		{[]string{"-config", filepath.Join(os.TempDir(), "not-found.yml"), "generate"}, exitError},
+		{[]string{"-config", unknownKey, "generate"}, exitError},
+		{[]string{"-config", config, "validate"}, exitError},
+		{[]string{"gen", ".", "."}, exitUsage},
+		{[]string{"gen", "-o", "-", filepath.Join(os.TempDir(), "not-found")}, exitError},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(tc.args, &stdout, &stderr); code != tc.code {
+			t.Errorf("%v exits with %d, expected %d: %s", tc.args, code, tc.code, stderr.String())
+		}
+	}
+}
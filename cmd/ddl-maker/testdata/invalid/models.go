@@ -0,0 +1,18 @@
+// Package invalid is the models whose methods can not be evaluated statically
+package invalid
+
+import (
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+// User XXX
+type User struct {
+	ID uint64
+}
+
+// PrimaryKey XXX
+func (u User) PrimaryKey() dialect.PrimaryKey {
+	columns := []string{"id"}
+	return mysql.AddPrimaryKey(columns...)
+}
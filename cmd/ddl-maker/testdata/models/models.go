@@ -0,0 +1,45 @@
+// Package models is the models loaded by the tests of ddl-maker
+package models
+
+import (
+	"time"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+// Author XXX
+type Author struct {
+	ID        uint64 `ddl:"auto"`
+	Name      string `ddl:"size=64"`
+	CreatedAt time.Time
+}
+
+// PrimaryKey XXX
+func (a Author) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+// Entry XXX
+type Entry struct {
+	ID       uint64 `ddl:"auto"`
+	AuthorID uint64
+	Title    string `ddl:"size=191"`
+}
+
+// Table XXX
+func (e Entry) Table() string {
+	return "blog_entry"
+}
+
+// PrimaryKey XXX
+func (e *Entry) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+// ForeignKeys XXX
+func (e Entry) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"author_id"}, []string{"id"}, "author"),
+	}
+}
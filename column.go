@@ -2,17 +2,35 @@ package ddlmaker
 
 import (
 	"fmt"
-	"log"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/kayac/ddl-maker/dialect"
+	"github.com/pkg/errors"
+)
+
+var (
+	numericDefaultRe  = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+	functionDefaultRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*\s*\(.*\)$`)
+	keywordDefaults   = map[string]bool{
+		"NULL":              true,
+		"TRUE":              true,
+		"FALSE":             true,
+		"CURRENT_TIMESTAMP": true,
+		"CURRENT_DATE":      true,
+		"CURRENT_TIME":      true,
+		"LOCALTIME":         true,
+		"LOCALTIMESTAMP":    true,
+	}
 )
 
 // column is mapping struct field value.
 type column struct {
 	name     string
 	typeName string
+	sqlType  string
+	values   []string
 	tag      string
 	dialect  dialect.Dialect
 }
@@ -27,7 +45,10 @@ func newColumn(name, typeName, tag string, d dialect.Dialect) column {
 }
 
 func (c column) size() (uint64, error) {
-	specs := c.specs()
+	specs, err := c.specs()
+	if err != nil {
+		return 0, err
+	}
 	if specs["size"] == "" {
 		return 0, nil
 	}
@@ -35,25 +56,81 @@ func (c column) size() (uint64, error) {
 	return strconv.ParseUint(specs["size"], 10, 64)
 }
 
-func (c column) specs() map[string]string {
-	elems := strings.Split(c.tag, ",")
-	specs := make(map[string]string, len(elems))
-	for _, elem := range elems {
-		ss := strings.Split(elem, "=")
-		switch len(ss) {
-		case 1:
-			specs[ss[0]] = ""
-		case 2:
-			specs[ss[0]] = ss[1]
+// precision returns the precision and scale of the column, which are set by precision and scale specs, or size=<precision>.<scale>
+func (c column) precision() (uint64, uint64, bool, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	precisionStr, scaleStr := specs["precision"], specs["scale"]
+	if ss := strings.SplitN(specs["size"], ".", 2); len(ss) == 2 {
+		if precisionStr != "" || scaleStr != "" {
+			return 0, 0, false, fmt.Errorf("size=%s and precision or scale are set", specs["size"])
+		}
+		precisionStr, scaleStr = ss[0], ss[1]
+	}
+	if precisionStr == "" {
+		if scaleStr != "" {
+			return 0, 0, false, fmt.Errorf("scale=%s is set without precision", scaleStr)
 		}
+		return 0, 0, false, nil
+	}
+
+	precision, err := strconv.ParseUint(precisionStr, 10, 64)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	var scale uint64
+	if scaleStr != "" {
+		scale, err = strconv.ParseUint(scaleStr, 10, 64)
+		if err != nil {
+			return 0, 0, false, err
+		}
+	}
+	if scale > precision {
+		return 0, 0, false, fmt.Errorf("scale %d is larger than precision %d", scale, precision)
+	}
+
+	return precision, scale, true, nil
+}
+
+// specs returns the specs of the tag, which is also validated by parseField
+func (c column) specs() (map[string]string, error) {
+	specs, _, err := c.quotedSpecs()
+	return specs, err
+}
+
+// quotedSpecs returns the specs of the tag and the names of the specs whose values are quoted
+func (c column) quotedSpecs() (map[string]string, map[string]bool, error) {
+	specs, quoted, err := parseQuotedTag(c.tag)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error tag parse %s", c.tag)
 	}
 
-	return specs
+	return specs, quoted, nil
+}
+
+// hasSpec reports whether the spec is set, which is false for an invalid tag whose error is returned by ToSQL
+func (c column) hasSpec(name string) bool {
+	specs, err := c.specs()
+	if err != nil {
+		return false
+	}
+	_, ok := specs[name]
+	return ok
 }
 
-func (c column) attribute() string {
+func (c column) attribute() (string, error) {
 	var attributes []string
-	specs := c.specs()
+	specs, quoted, err := c.quotedSpecs()
+	if err != nil {
+		return "", err
+	}
+
+	if generated := c.generatedSQL(false); generated != "" {
+		attributes = append(attributes, generated)
+	}
 
 	if _, ok := specs["null"]; ok {
 		attributes = append(attributes, "NULL")
@@ -63,39 +140,317 @@ func (c column) attribute() string {
 
 	if defaultVal, ok := specs["default"]; ok {
 		attributes = append(attributes, "DEFAULT")
-		attributes = append(attributes, defaultVal)
+		attributes = append(attributes, defaultSQL(defaultVal, quoted["default"], c.dialect))
+	}
+
+	if onUpdate, ok := specs["onupdate"]; ok {
+		if d, ok := c.dialect.(dialect.OnUpdate); ok {
+			if onUpdate == "" {
+				onUpdate = "CURRENT_TIMESTAMP"
+				size, err := c.size()
+				if err != nil {
+					return "", err
+				}
+				if size > 0 {
+					onUpdate = fmt.Sprintf("CURRENT_TIMESTAMP(%d)", size)
+				}
+			}
+			attributes = append(attributes, d.OnUpdateSQL(onUpdate))
+		}
 	}
 
 	if _, ok := specs["auto"]; ok {
 		attributes = append(attributes, c.dialect.AutoIncrement())
 	}
 
-	return strings.Join(attributes, " ")
+	if comment := specs["comment"]; comment != "" {
+		if commenter, ok := c.dialect.(dialect.Commenter); ok {
+			attributes = append(attributes, commenter.CommentSQL(comment))
+		}
+	}
+
+	return strings.Join(attributes, " "), nil
+}
+
+// defaultSQL returns the default value as it is if it is a number, a keyword such as CURRENT_TIMESTAMP,
+// a function call such as now() or an expression in parentheses such as (uuid()), otherwise as a string literal.
+// A quoted value, such as '0001', is always a string literal.
+func defaultSQL(value string, quoted bool, d dialect.Dialect) string {
+	if !quoted {
+		switch {
+		case numericDefaultRe.MatchString(value),
+			keywordDefaults[strings.ToUpper(value)],
+			functionDefaultRe.MatchString(value),
+			strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"),
+			len(value) > 1 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+			return value
+		}
+	}
+
+	if quoter, ok := d.(dialect.StringQuoter); ok {
+		return quoter.QuoteString(value)
+	}
+
+	return fmt.Sprintf("'%s'", strings.Replace(value, "'", "''", -1))
 }
 
 func (c column) Name() string {
 	return c.name
 }
 
-// ToSQL is convert struct value to sql.
-func (c column) ToSQL() string {
-	var columnType string
-	specs := c.specs()
+// Generated reports whether the column is generated from an expression, which must not be written to
+func (c column) Generated() bool {
+	return c.hasSpec("generated")
+}
 
-	if typeName, ok := specs["type"]; ok {
-		columnType = typeName
-	} else {
-		columnType = c.typeName
+// AutoIncrement reports whether the column is declared with auto tag
+func (c column) AutoIncrement() bool {
+	return c.hasSpec("auto")
+}
+
+// validateGenerated checks that the specs of a generated column are supported
+func (c column) validateGenerated() error {
+	specs, err := c.specs()
+	if err != nil {
+		return err
+	}
+	if _, ok := specs["generated"]; !ok {
+		if _, ok := specs["stored"]; ok {
+			return fmt.Errorf("stored is set without generated")
+		}
+		return nil
+	}
+
+	if specs["generated"] == "" {
+		return fmt.Errorf("generated has no expression")
+	}
+	if _, ok := c.dialect.(dialect.Generator); !ok {
+		return fmt.Errorf("%T does not support generated column", c.dialect)
+	}
+	for _, spec := range []string{"default", "onupdate", "auto"} {
+		if _, ok := specs[spec]; ok {
+			return fmt.Errorf("generated column can not have %s", spec)
+		}
 	}
 
+	return nil
+}
+
+// Comment returns the comment spec, which is empty for an invalid tag whose error is returned by ToSQL
+func (c column) Comment() string {
+	specs, err := c.specs()
+	if err != nil {
+		return ""
+	}
+	return specs["comment"]
+}
+
+// ToSQL is convert struct value to sql.
+func (c column) ToSQL() (string, error) {
 	name := c.dialect.Quote(c.name)
-	size, err := c.size()
+
+	specs, err := c.specs()
 	if err != nil {
-		log.Fatalf("error size parse error %v", err)
+		return "", err
 	}
+	sql, err := c.typeSQL(true)
+	if err != nil {
+		return "", err
+	}
+	if err := c.validateGenerated(); err != nil {
+		return "", err
+	}
+	if _, ok := specs["onupdate"]; ok {
+		if _, ok := c.dialect.(dialect.OnUpdate); !ok {
+			return "", fmt.Errorf("%T does not support onupdate", c.dialect)
+		}
+	}
+	attribute, err := c.attribute()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s %s", name, sql, attribute), nil
+}
 
-	sql := c.dialect.ToSQL(columnType, size)
-	attribute := c.attribute()
+// TypeSQL returns the sql type with the collation, without the CHECK constraint of the enum values written by dialect.EnumCheck
+func (c column) TypeSQL() (string, error) {
+	return c.typeSQL(false)
+}
+
+// Null reports whether the column is declared with null tag
+func (c column) Null() bool {
+	return c.hasSpec("null")
+}
+
+// DefaultSQL returns the default value written by ToSQL, and false if the column has no default
+func (c column) DefaultSQL() (string, bool) {
+	specs, quoted, err := c.quotedSpecs()
+	if err != nil {
+		return "", false
+	}
+	value, ok := specs["default"]
+	if !ok {
+		return "", false
+	}
+
+	return defaultSQL(value, quoted["default"], c.dialect), true
+}
+
+// GeneratedExpr returns the expression of a generated column, and false if the column is not generated
+func (c column) GeneratedExpr() (string, bool) {
+	specs, err := c.specs()
+	if err != nil {
+		return "", false
+	}
+	expr, ok := specs["generated"]
+	return expr, ok
+}
+
+// generatedSQL returns the generated column clause written by ToSQL, in which the expression is normalized if normalize is true
+func (c column) generatedSQL(normalize bool) string {
+	specs, err := c.specs()
+	if err != nil {
+		return ""
+	}
+	expr, ok := specs["generated"]
+	d, isGenerator := c.dialect.(dialect.Generator)
+	if !ok || !isGenerator {
+		return ""
+	}
+	if normalize {
+		expr = normalizeExpr(expr)
+	}
+	_, stored := specs["stored"]
+
+	return d.GeneratedSQL(expr, stored)
+}
+
+// collation returns the sql type written by ToSQL without the character set and the collation, and them
+func (c column) collation() (string, string, string, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return "", "", "", err
+	}
+	typeSQL, err := c.baseTypeSQL(true)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return typeSQL, specs["charset"], specs["collate"], nil
+}
+
+// typeSQL returns the sql type with the collation, and with the CHECK constraint of the enum values if enumCheck is true
+func (c column) typeSQL(enumCheck bool) (string, error) {
+	sql, err := c.baseTypeSQL(enumCheck)
+	if err != nil {
+		return "", err
+	}
+
+	return c.collateSQL(sql)
+}
+
+// baseTypeSQL returns the sql type without the collation
+func (c column) baseTypeSQL(enumCheck bool) (string, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return "", err
+	}
+	typeName, ok := specs["type"]
+	if !ok {
+		typeName = c.typeName
+	}
+
+	// the sql type declared by DDLType() of the field type is used unless type spec is set
+	sql := c.sqlType
+	if ok || sql == "" {
+		return c.typeToSQL(typeName, enumCheck)
+	}
+
+	return sql, nil
+}
+
+// collateSQL returns the sql type with the character set and the collation set by charset and collate specs
+func (c column) collateSQL(sql string) (string, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return "", err
+	}
+	charset, collate := specs["charset"], specs["collate"]
+	if charset == "" && collate == "" {
+		return sql, nil
+	}
+
+	d, ok := c.dialect.(dialect.Collator)
+	if !ok {
+		return "", fmt.Errorf("%T does not support charset and collate", c.dialect)
+	}
+
+	return d.CollateSQL(sql, charset, collate)
+}
+
+// enumValues returns the values of an enum column, which are set by values spec or EnumValues() of the field type
+func (c column) enumValues() ([]string, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := specs["values"]; ok {
+		return parseValues(list)
+	}
+
+	return c.values, nil
+}
+
+// writtenEnumValues returns the enum values written by ToSQL, which are nil if the sql type is declared by DDLType() of the field type
+func (c column) writtenEnumValues() ([]string, error) {
+	specs, err := c.specs()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := specs["type"]; !ok && c.sqlType != "" {
+		return nil, nil
+	}
+
+	return c.enumValues()
+}
+
+func (c column) typeToSQL(typeName string, enumCheck bool) (string, error) {
+	values, err := c.enumValues()
+	if err != nil {
+		return "", errors.Wrap(err, "error values parse")
+	}
+	if len(values) > 0 {
+		d, ok := c.dialect.(dialect.Enum)
+		if !ok {
+			return "", fmt.Errorf("%T does not support enum", c.dialect)
+		}
+		size, err := c.size()
+		if err != nil {
+			return "", errors.Wrap(err, "error size parse")
+		}
+		if ec, ok := d.(dialect.EnumCheck); ok && !enumCheck {
+			return ec.EnumTypeSQL(typeName, size)
+		}
+		return d.EnumToSQL(c.name, typeName, size, values)
+	}
+
+	precision, scale, ok, err := c.precision()
+	if err != nil {
+		return "", errors.Wrap(err, "error precision parse")
+	}
+	if ok {
+		d, ok := c.dialect.(dialect.Decimal)
+		if !ok {
+			return "", fmt.Errorf("%T does not support precision", c.dialect)
+		}
+		return d.DecimalToSQL(typeName, precision, scale)
+	}
+
+	size, err := c.size()
+	if err != nil {
+		return "", errors.Wrap(err, "error size parse")
+	}
 
-	return fmt.Sprintf("%s %s %s", name, sql, attribute)
+	return c.dialect.ToSQL(typeName, size)
 }
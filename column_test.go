@@ -2,9 +2,12 @@ package ddlmaker
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/kayac/ddl-maker/dialect"
 	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
 )
 
 func TestSize(t *testing.T) {
@@ -38,31 +41,74 @@ func TestSpecs(t *testing.T) {
 		"default": "jon",
 	}
 
-	if !reflect.DeepEqual(c.specs(), specs) {
-		t.Fatalf("parse tag error. result: %q", c.specs())
+	if result, err := c.specs(); err != nil || !reflect.DeepEqual(result, specs) {
+		t.Fatalf("parse tag error. result: %q, %v", result, err)
+	}
+
+	c = column{
+		name:     "name",
+		typeName: "string",
+		tag:      "default='jon",
+		dialect:  mysql.MySQL{},
+	}
+	if _, err := c.specs(); err == nil {
+		t.Fatal("error invalid tag")
+	}
+	if _, err := c.ToSQL(); err == nil {
+		t.Fatal("error invalid tag to sql")
 	}
 }
 
 func TestAttribute(t *testing.T) {
 	c := column{dialect: mysql.MySQL{}}
 
-	if c.attribute() != "NOT NULL" {
-		t.Fatalf("error column attribute. result:%s", c.attribute())
+	if attribute, err := c.attribute(); err != nil || attribute != "NOT NULL" {
+		t.Fatalf("error column attribute. result:%s, %v", attribute, err)
 	}
 
 	c.tag = "null"
-	if c.attribute() != "NULL" {
-		t.Fatalf("error column attribute. result:%s", c.attribute())
+	if attribute, err := c.attribute(); err != nil || attribute != "NULL" {
+		t.Fatalf("error column attribute. result:%s, %v", attribute, err)
 	}
 
 	c.tag = "default=0"
-	if c.attribute() != "NOT NULL DEFAULT 0" {
-		t.Fatalf("error column attribute. result:%s", c.attribute())
+	if attribute, err := c.attribute(); err != nil || attribute != "NOT NULL DEFAULT 0" {
+		t.Fatalf("error column attribute. result:%s, %v", attribute, err)
 	}
 
 	c.tag = "auto"
-	if c.attribute() != "NOT NULL AUTO_INCREMENT" {
-		t.Fatalf("error column attribute. result:%s", c.attribute())
+	if attribute, err := c.attribute(); err != nil || attribute != "NOT NULL AUTO_INCREMENT" {
+		t.Fatalf("error column attribute. result:%s, %v", attribute, err)
+	}
+
+	testcases := []struct {
+		tag       string
+		attribute string
+	}{
+		{"default=-1.5", "NOT NULL DEFAULT -1.5"},
+		{"default=draft", "NOT NULL DEFAULT 'draft'"},
+		{"default='it''s, a \\\\ memo'", "NOT NULL DEFAULT 'it''s, a \\\\ memo'"},
+		{"default='0001'", "NOT NULL DEFAULT '0001'"},
+		{"default='NULL'", "NOT NULL DEFAULT 'NULL'"},
+		{"default='now()'", "NOT NULL DEFAULT 'now()'"},
+		{"null,default=null", "NULL DEFAULT null"},
+		{"default=CURRENT_TIMESTAMP", "NOT NULL DEFAULT CURRENT_TIMESTAMP"},
+		{"default=CURRENT_TIMESTAMP(6)", "NOT NULL DEFAULT CURRENT_TIMESTAMP(6)"},
+		{"default=(uuid())", "NOT NULL DEFAULT (uuid())"},
+		{"default=CURRENT_TIMESTAMP,onupdate", "NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP"},
+		{"size=6,default=CURRENT_TIMESTAMP(6),onupdate", "NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)"},
+		{"null,onupdate=CURRENT_TIMESTAMP", "NULL ON UPDATE CURRENT_TIMESTAMP"},
+	}
+	for _, tc := range testcases {
+		c.tag = tc.tag
+		if attribute, err := c.attribute(); err != nil || attribute != tc.attribute {
+			t.Fatalf("error column attribute %s. result:%s, %v", tc.tag, attribute, err)
+		}
+	}
+
+	c = column{dialect: postgres.PostgreSQL{}, typeName: "time.Time", tag: "default=CURRENT_TIMESTAMP,onupdate"}
+	if _, err := c.ToSQL(); err == nil || !strings.Contains(err.Error(), "does not support onupdate") {
+		t.Fatal("error column onupdate of unsupported dialect", err)
 	}
 }
 
@@ -73,13 +119,13 @@ func TestToSQL(t *testing.T) {
 		dialect:  mysql.MySQL{},
 	}
 
-	if c.ToSQL() != "`id` BIGINT NOT NULL" {
-		t.Fatalf("error ToSQL. result: %s", c.ToSQL())
+	if sql, err := c.ToSQL(); sql != "`id` BIGINT NOT NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
 	}
 
 	c.typeName = "uint64"
-	if c.ToSQL() != "`id` BIGINT unsigned NOT NULL" {
-		t.Fatalf("error ToSQL. result: %s", c.ToSQL())
+	if sql, err := c.ToSQL(); sql != "`id` BIGINT unsigned NOT NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
 	}
 
 	c = column{
@@ -89,8 +135,8 @@ func TestToSQL(t *testing.T) {
 		dialect:  mysql.MySQL{},
 	}
 
-	if c.ToSQL() != "`description` VARCHAR(20) NULL" {
-		t.Fatalf("error ToSQL. result: %s", c.ToSQL())
+	if sql, err := c.ToSQL(); sql != "`description` VARCHAR(20) NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
 	}
 
 	c = column{
@@ -100,7 +146,171 @@ func TestToSQL(t *testing.T) {
 		dialect:  mysql.MySQL{},
 	}
 
-	if c.ToSQL() != "`comment` TEXT NULL" {
-		t.Fatalf("error ToSQL. result: %s", c.ToSQL())
+	if sql, err := c.ToSQL(); sql != "`comment` TEXT NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
+	}
+
+	c = column{
+		typeName: "string",
+		name:     "comment",
+		tag:      "size=a",
+		dialect:  mysql.MySQL{},
+	}
+
+	if _, err := c.ToSQL(); err == nil {
+		t.Fatal("error ToSQL. invalid size is parsed")
+	}
+
+	c = column{
+		typeName: "string",
+		name:     "memo",
+		tag:      "null, comment='it''s a memo, \\\\ escaped'",
+		dialect:  mysql.MySQL{},
+	}
+
+	if sql, err := c.ToSQL(); sql != "`memo` VARCHAR(191) NULL COMMENT 'it''s a memo, \\\\ escaped'" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
+	}
+	if c.Comment() != "it's a memo, \\ escaped" {
+		t.Fatalf("error Comment. result: %s", c.Comment())
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	testcases := []struct {
+		tag    string
+		output string
+	}{
+		{"", "`price` DECIMAL(65,30) NOT NULL"},
+		{"size=12", "`price` DECIMAL(12,0) NOT NULL"},
+		{"size=12.2", "`price` DECIMAL(12,2) NOT NULL"},
+		{"precision=12,scale=2", "`price` DECIMAL(12,2) NOT NULL"},
+		{"precision=12", "`price` DECIMAL(12,0) NOT NULL"},
+		{"type=decimal,precision=8,scale=3", "`price` DECIMAL(8,3) NOT NULL"},
+	}
+	for _, tc := range testcases {
+		c := column{
+			typeName: "decimal.Decimal",
+			name:     "price",
+			tag:      tc.tag,
+			dialect:  mysql.MySQL{},
+		}
+		if sql, err := c.ToSQL(); sql != tc.output || err != nil {
+			t.Fatalf("error ToSQL %s. result: %s, %v", tc.tag, sql, err)
+		}
+	}
+
+	errorTags := []string{"size=12.a", "scale=2", "precision=2,scale=3", "size=12.2,scale=2", "precision=66"}
+	for _, tag := range errorTags {
+		c := column{
+			typeName: "decimal.Decimal",
+			name:     "price",
+			tag:      tag,
+			dialect:  mysql.MySQL{},
+		}
+		if _, err := c.ToSQL(); err == nil {
+			t.Fatalf("error ToSQL %s. invalid precision is parsed", tag)
+		}
+	}
+
+	c := column{
+		typeName: "string",
+		name:     "price",
+		tag:      "size=12.2",
+		dialect:  mysql.MySQL{},
+	}
+	if _, err := c.ToSQL(); err == nil {
+		t.Fatal("error ToSQL. precision of string is parsed")
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	c := column{
+		typeName: "int64",
+		name:     "total",
+		tag:      "generated=(price * quantity),stored",
+		dialect:  mysql.MySQL{},
+	}
+
+	if sql, err := c.ToSQL(); sql != "`total` BIGINT GENERATED ALWAYS AS ((price * quantity)) STORED NOT NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
+	}
+	if !c.Generated() {
+		t.Fatal("error Generated. generated column is not marked")
+	}
+
+	c.tag = "null,generated=price * quantity"
+	if sql, err := c.ToSQL(); sql != "`total` BIGINT GENERATED ALWAYS AS (price * quantity) VIRTUAL NULL" || err != nil {
+		t.Fatalf("error ToSQL. result: %s, %v", sql, err)
+	}
+
+	for _, tag := range []string{"generated", "stored", "generated=price,default=0", "generated=price,auto"} {
+		c.tag = tag
+		if _, err := c.ToSQL(); err == nil {
+			t.Fatalf("error ToSQL. invalid generated column %s is accepted", tag)
+		}
+	}
+
+	c.tag = "null"
+	if c.Generated() {
+		t.Fatal("error Generated. plain column is marked")
+	}
+}
+
+func TestAlterColumn(t *testing.T) {
+	pg := postgres.PostgreSQL{}
+
+	testcases := []struct {
+		column column
+		sql    string
+	}{
+		{
+			column{typeName: "bool", name: "named", tag: "generated=name IS NOT NULL,stored", dialect: pg},
+			`ALTER COLUMN "named" TYPE BOOLEAN, ALTER COLUMN "named" SET NOT NULL, ALTER COLUMN "named" SET EXPRESSION AS (name IS NOT NULL)`,
+		},
+		{
+			column{typeName: "string", name: "status", tag: "null,size=16,type=enum,values=(a|b),collate=C,default='a'", dialect: pg},
+			`ALTER COLUMN "status" TYPE VARCHAR(16) COLLATE "C", ALTER COLUMN "status" DROP NOT NULL, ALTER COLUMN "status" SET DEFAULT 'a'`,
+		},
+	}
+	for _, tc := range testcases {
+		var c dialect.ColumnDefinition = tc.column
+		if sql, err := pg.AlterColumnSQL(c); sql != tc.sql || err != nil {
+			t.Fatalf("error AlterColumnSQL. result: %s, %v", sql, err)
+		}
+	}
+}
+
+func TestCollate(t *testing.T) {
+	testcases := []struct {
+		column column
+		sql    string
+	}{
+		{
+			column{typeName: "string", name: "token", tag: "size=64,collate=utf8mb4_bin", dialect: mysql.MySQL{}},
+			"`token` VARCHAR(64) COLLATE utf8mb4_bin NOT NULL",
+		},
+		{
+			column{typeName: "string", name: "hash", tag: "size=64,charset=ascii,collate=ascii_bin", dialect: mysql.MySQL{}},
+			"`hash` VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL",
+		},
+		{
+			column{typeName: "string", name: "token", tag: "collate=C", dialect: postgres.PostgreSQL{}},
+			`"token" TEXT COLLATE "C" NOT NULL`,
+		},
+		{
+			column{typeName: "string", name: "status", tag: "type=enum,values=(a|b),collate=C", dialect: postgres.PostgreSQL{}},
+			`"status" TEXT COLLATE "C" CHECK ("status" IN ('a', 'b')) NOT NULL`,
+		},
+	}
+	for _, tc := range testcases {
+		if sql, err := tc.column.ToSQL(); sql != tc.sql || err != nil {
+			t.Fatalf("error ToSQL. result: %s, %v", sql, err)
+		}
+	}
+
+	c := column{typeName: "string", name: "hash", tag: "charset=ascii", dialect: postgres.PostgreSQL{}}
+	if _, err := c.ToSQL(); err == nil {
+		t.Fatal("error ToSQL. charset of postgres is accepted")
 	}
 }
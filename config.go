@@ -1,9 +1,15 @@
 package ddlmaker
 
+import (
+	"github.com/kayac/ddl-maker/dialect"
+)
+
 // Config set user environment
 type Config struct {
 	OutFilePath string
 	DB          DBConfig
+	// Tables overrides the tables by their names
+	Tables map[string]TableConfig
 }
 
 // DBConfig set user db environment
@@ -12,3 +18,13 @@ type DBConfig struct {
 	Engine  string
 	Charset string
 }
+
+// TableConfig overrides a table built from a struct
+type TableConfig struct {
+	// Skip removes the table from the ddl
+	Skip bool
+	// Comment replaces the comment of the table if not empty
+	Comment string
+	// Options are merged into the table options, such as mysql.TableOptionEngine
+	Options dialect.TableOptions
+}
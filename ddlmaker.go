@@ -1,6 +1,7 @@
 package ddlmaker
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -30,6 +31,8 @@ type DDLMaker struct {
 	Dialect dialect.Dialect
 	Structs []interface{}
 	Tables  []dialect.Table
+	// addedTables are the tables added by AddTables
+	addedTables []dialect.Table
 }
 
 // New creates a DDLMaker and returns it.
@@ -46,50 +49,128 @@ func New(conf Config) (*DDLMaker, error) {
 }
 
 // AddStruct XXX
+// It returns an error if a struct is given twice, and adds none of ss then.
 func (dm *DDLMaker) AddStruct(ss ...interface{}) error {
 	pkgs := make(map[string]bool)
 
+	var structs []interface{}
 	for _, s := range ss {
 		if s == nil {
 			return fmt.Errorf("nil is not supported")
 		}
 
-		val := reflect.Indirect(reflect.ValueOf(s))
-		rt := val.Type()
-
-		structName := fmt.Sprintf("%s.%s", rt.PkgPath(), rt.Name())
+		structName := fullStructName(s)
 		if pkgs[structName] {
 			return fmt.Errorf("%s is already added", structName)
 		}
 
-		dm.Structs = append(dm.Structs, s)
+		structs = append(structs, s)
 		pkgs[structName] = true
 	}
+	dm.Structs = append(dm.Structs, structs...)
+
+	return nil
+}
+
+// AddTables adds the tables which are not built from structs, such as the tables loaded by the static package
+func (dm *DDLMaker) AddTables(tables ...dialect.Table) error {
+	for _, t := range tables {
+		if t == nil {
+			return fmt.Errorf("nil is not supported")
+		}
+		dm.addedTables = append(dm.addedTables, t)
+	}
 
 	return nil
 }
 
+// fullStructName returns the name of the struct of s with its package path
+func fullStructName(s interface{}) string {
+	rt := reflect.Indirect(reflect.ValueOf(s)).Type()
+	return fmt.Sprintf("%s.%s", rt.PkgPath(), rt.Name())
+}
+
+// RegisterType maps the Go type t, which is a type name such as "uuid.UUID", a reflect.Type or a value of the type,
+// to the sql type returned by f, so that fields of the type need no type tag.
+func (dm *DDLMaker) RegisterType(t interface{}, f func(size uint64) string) error {
+	registry, ok := dm.Dialect.(dialect.TypeRegistry)
+	if !ok {
+		return fmt.Errorf("%T does not support RegisterType", dm.Dialect)
+	}
+
+	return registry.RegisterType(t, f)
+}
+
 // Generate ddl file
 func (dm *DDLMaker) Generate() error {
 	log.Printf("start generate %s \n", dm.config.OutFilePath)
-	dm.parse()
 
-	file, err := os.Create(dm.config.OutFilePath)
+	var ddl bytes.Buffer
+	err := dm.GenerateTo(&ddl)
+	if err != nil {
+		return err
+	}
+	if err := writeDDLFile(dm.config.OutFilePath, ddl.Bytes()); err != nil {
+		return err
+	}
+
+	log.Printf("done generate %s \n", dm.config.OutFilePath)
+
+	return nil
+}
+
+// writeDDLFile creates path and writes ddl to it.
+// ddl is rendered before, so that an error keeps the existing file.
+func writeDDLFile(path string, ddl []byte) error {
+	file, err := os.Create(path)
 	if err != nil {
 		return errors.Wrap(err, "error create ddl file")
 	}
-	defer file.Close()
+	if _, err := file.Write(ddl); err != nil {
+		file.Close()
+		return errors.Wrap(err, "error write ddl file")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "error close ddl file")
+	}
+
+	return nil
+}
+
+// GenerateTo writes ddl to w
+func (dm *DDLMaker) GenerateTo(w io.Writer) error {
+	if err := dm.parse(); err != nil {
+		return errors.Wrap(err, "error parse")
+	}
 
-	err = dm.generate(file)
+	err := dm.generate(w)
 	if err != nil {
 		return errors.Wrap(err, "error generate")
 	}
 
-	log.Printf("done generate %s \n", dm.config.OutFilePath)
-
 	return nil
 }
 
+// GenerateBytes returns ddl as bytes
+func (dm *DDLMaker) GenerateBytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := dm.GenerateTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GenerateString returns ddl as string
+func (dm *DDLMaker) GenerateString() (string, error) {
+	b, err := dm.GenerateBytes()
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func (dm *DDLMaker) generate(w io.Writer) error {
 	header, err := template.New("header").Parse(dm.Dialect.HeaderTemplate())
 	if err != nil {
@@ -106,18 +187,48 @@ func (dm *DDLMaker) generate(w io.Writer) error {
 		return errors.Wrap(err, "error parse template")
 	}
 
+	tables, deferred := sortTables(dm.Tables, deferCycles(dm.Dialect))
+	alter, err := dm.alterTemplate(deferred)
+	if err != nil {
+		return err
+	}
+
 	if err := header.Execute(w, nil); err != nil {
 		return errors.Wrap(err, "template execute error")
 	}
-	for _, table := range dm.Tables {
+	for _, table := range tables {
 		err := tmpl.Execute(w, table)
 		if err != nil {
 			return errors.Wrap(err, "template execute error")
 		}
 	}
+	for _, diff := range deferred {
+		if err := alter.Execute(w, diff); err != nil {
+			return errors.Wrap(err, "template execute error")
+		}
+	}
 	if err := footer.Execute(w, nil); err != nil {
 		return errors.Wrap(err, "template execute error")
 	}
 
 	return nil
 }
+
+// alterTemplate returns the template to add the foreign keys in a cycle after all tables are created
+func (dm *DDLMaker) alterTemplate(deferred []tableDiff) (*template.Template, error) {
+	if len(deferred) == 0 {
+		return nil, nil
+	}
+
+	migrator, ok := dm.Dialect.(dialect.Migrator)
+	if !ok {
+		return nil, fmt.Errorf("%T can not add foreign keys in a cycle of %s", dm.Dialect, deferred[0].Name())
+	}
+
+	alter, err := template.New("alter").Parse(migrator.AlterTableTemplate())
+	if err != nil {
+		return nil, errors.Wrap(err, "error parse alter template")
+	}
+
+	return alter, nil
+}
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"database/sql"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kayac/ddl-maker/dialect"
 	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
 )
 
 type Test1 struct {
@@ -34,6 +38,23 @@ func (t2 *Test2) PrimaryKey() dialect.PrimaryKey {
 	return mysql.AddPrimaryKey("id", "created_at")
 }
 
+type Test3 struct {
+	ID        int64 `ddl:"auto"`
+	Name      string
+	CreatedAt time.Time
+}
+
+func (t3 Test3) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (t3 Test3) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		postgres.AddUniqueIndex("name_uniq_idx", "name"),
+		postgres.AddIndex("created_at_idx", "created_at"),
+	}
+}
+
 func TestNew(t *testing.T) {
 	conf := Config{}
 	_, err := New(conf)
@@ -125,7 +146,10 @@ CREATE TABLE %s (
 	if err != nil {
 		t.Fatal("error add struct", err)
 	}
-	dm.parse()
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
 
 	var ddl1 bytes.Buffer
 	err = dm.generate(&ddl1)
@@ -150,7 +174,10 @@ CREATE TABLE %s (
 	if err != nil {
 		t.Fatal("error add pointer struct", err)
 	}
-	dm2.parse()
+	err = dm2.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
 
 	var ddl2 bytes.Buffer
 	err = dm2.generate(&ddl2)
@@ -162,3 +189,484 @@ CREATE TABLE %s (
 		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl2.String(), generatedDDL2)
 	}
 }
+
+func TestGeneratePostgres(t *testing.T) {
+	generatedDDL := `BEGIN;
+
+DROP TABLE IF EXISTS "test3" CASCADE;
+
+CREATE TABLE "test3" (
+    "id" BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    "name" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "created_at_idx" ON "test3" ("created_at");
+CREATE UNIQUE INDEX "name_uniq_idx" ON "test3" ("name");
+
+COMMIT;
+`
+
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "postgres"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+
+	err = dm.AddStruct(&Test3{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generate(&ddl)
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
+
+func TestGenerateSQLite(t *testing.T) {
+	generatedDDL := `PRAGMA foreign_keys=OFF;
+
+DROP TABLE IF EXISTS "test1";
+
+CREATE TABLE "test1" (
+    "id" INTEGER NOT NULL,
+    "name" TEXT NOT NULL,
+    "created_at" DATETIME NOT NULL,
+    "updated_at" DATETIME NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+
+DROP TABLE IF EXISTS "test3";
+
+CREATE TABLE "test3" (
+    "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    "name" TEXT NOT NULL,
+    "created_at" DATETIME NOT NULL
+);
+CREATE INDEX "created_at_idx" ON "test3" ("created_at");
+CREATE UNIQUE INDEX "name_uniq_idx" ON "test3" ("name");
+
+PRAGMA foreign_keys=ON;
+`
+
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "sqlite3"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+
+	err = dm.AddStruct(&Test1{}, &Test3{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generate(&ddl)
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "sqlite3"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(AutoLog{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	if _, err := dm.GenerateString(); err == nil || !strings.Contains(err.Error(), "auto column id must be the only column of the primary key") {
+		t.Fatal("error generate auto column of composite primary key", err)
+	}
+}
+
+type AutoLog struct {
+	ID        uint64 `ddl:"auto"`
+	CreatedAt time.Time
+}
+
+func (l AutoLog) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id", "created_at")
+}
+
+func TestGenerateTo(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+
+	err = dm.AddStruct(&Test1{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.GenerateTo(&ddl)
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	// generating twice writes the same ddl
+	ddlStr, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if ddlStr != ddl.String() {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddlStr, ddl.String())
+	}
+
+	dm.AddStruct(T2{})
+	if _, err := dm.GenerateBytes(); err == nil {
+		t.Fatal("error generate ddl of unknown type")
+	}
+}
+
+type Test4 struct {
+	ID   uint64 `ddl:"comment=identifier"`
+	Memo string `ddl:"null,comment='user''s memo, free text'"`
+}
+
+func (t4 Test4) Comment() string {
+	return "memos of user"
+}
+
+func (t4 Test4) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+type PostgresTest4 struct {
+	Test4
+}
+
+func (t4 PostgresTest4) Table() string {
+	return "test4"
+}
+
+func (t4 PostgresTest4) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func TestGenerateComment(t *testing.T) {
+	generatedDDLs := map[string]struct {
+		s   interface{}
+		ddl string
+	}{
+		"mysql": {Test4{}, "SET foreign_key_checks=0;\n" + `
+DROP TABLE IF EXISTS ` + "`test4`" + `;
+
+CREATE TABLE ` + "`test4`" + ` (
+    ` + "`id`" + ` BIGINT unsigned NOT NULL COMMENT 'identifier',
+    ` + "`memo`" + ` VARCHAR(191) NULL COMMENT 'user''s memo, free text',
+    PRIMARY KEY (` + "`id`" + `)
+) ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4 COMMENT='memos of user';
+
+SET foreign_key_checks=1;
+`},
+		"postgres": {PostgresTest4{}, `BEGIN;
+
+DROP TABLE IF EXISTS "test4" CASCADE;
+
+CREATE TABLE "test4" (
+    "id" BIGINT NOT NULL,
+    "memo" TEXT NULL,
+    PRIMARY KEY ("id")
+);
+COMMENT ON TABLE "test4" IS 'memos of user';
+COMMENT ON COLUMN "test4"."id" IS 'identifier';
+COMMENT ON COLUMN "test4"."memo" IS 'user''s memo, free text';
+
+COMMIT;
+`},
+		"sqlite3": {Test4{}, `PRAGMA foreign_keys=OFF;
+
+DROP TABLE IF EXISTS "test4";
+
+CREATE TABLE "test4" (
+    "id" INTEGER NOT NULL,
+    "memo" TEXT NULL,
+    PRIMARY KEY ("id")
+);
+
+PRAGMA foreign_keys=ON;
+`},
+	}
+
+	for driver, generatedDDL := range generatedDDLs {
+		dm, err := New(Config{
+			DB: DBConfig{Driver: driver, Engine: "InnoDB", Charset: "utf8mb4"},
+		})
+		if err != nil {
+			t.Fatal("error new maker", err)
+		}
+		err = dm.AddStruct(generatedDDL.s)
+		if err != nil {
+			t.Fatal("error add struct", err)
+		}
+
+		ddl, err := dm.GenerateString()
+		if err != nil {
+			t.Fatal("error generate ddl", err)
+		}
+		if ddl != generatedDDL.ddl {
+			t.Fatalf("%s generatedDDL: %s \n checkDDLL: %s \n", driver, ddl, generatedDDL.ddl)
+		}
+	}
+}
+
+type Test5 struct {
+	ID    uint64
+	Price uint64
+	Stock uint64
+}
+
+func (t5 Test5) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (t5 Test5) Checks() dialect.Checks {
+	return dialect.Checks{
+		mysql.AddCheck("stock_positive", "stock >= 0"),
+		mysql.AddCheck("", "price < 100000"),
+	}
+}
+
+func TestGenerateCheck(t *testing.T) {
+	generatedDDLs := map[string]string{
+		"mysql": "SET foreign_key_checks=0;\n" + `
+DROP TABLE IF EXISTS ` + "`test5`" + `;
+
+CREATE TABLE ` + "`test5`" + ` (
+    ` + "`id`" + ` BIGINT unsigned NOT NULL,
+    ` + "`price`" + ` BIGINT unsigned NOT NULL,
+    ` + "`stock`" + ` BIGINT unsigned NOT NULL,
+    CHECK (price < 100000),
+    CONSTRAINT ` + "`stock_positive`" + ` CHECK (stock >= 0),
+    PRIMARY KEY (` + "`id`" + `)
+) ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4;
+
+SET foreign_key_checks=1;
+`,
+		"sqlite3": `PRAGMA foreign_keys=OFF;
+
+DROP TABLE IF EXISTS "test5";
+
+CREATE TABLE "test5" (
+    "id" INTEGER NOT NULL,
+    "price" INTEGER NOT NULL,
+    "stock" INTEGER NOT NULL,
+    PRIMARY KEY ("id"),
+    CHECK (price < 100000),
+    CONSTRAINT "stock_positive" CHECK (stock >= 0)
+);
+
+PRAGMA foreign_keys=ON;
+`,
+	}
+
+	for driver, generatedDDL := range generatedDDLs {
+		dm, err := New(Config{
+			DB: DBConfig{Driver: driver, Engine: "InnoDB", Charset: "utf8mb4"},
+		})
+		if err != nil {
+			t.Fatal("error new maker", err)
+		}
+		err = dm.AddStruct(Test5{})
+		if err != nil {
+			t.Fatal("error add struct", err)
+		}
+
+		ddl, err := dm.GenerateString()
+		if err != nil {
+			t.Fatal("error generate ddl", err)
+		}
+		if ddl != generatedDDL {
+			t.Fatalf("%s generatedDDL: %s \n checkDDLL: %s \n", driver, ddl, generatedDDL)
+		}
+	}
+}
+
+type Test6 struct {
+	ID uint64 `ddl:"auto"`
+}
+
+func (t6 Test6) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (t6 Test6) TableOptions() dialect.TableOptions {
+	return dialect.TableOptions{
+		mysql.TableOptionCollate:       "utf8mb4_bin",
+		mysql.TableOptionRowFormat:     "COMPRESSED",
+		mysql.TableOptionAutoIncrement: "1000000",
+	}
+}
+
+func TestGenerateTableOptions(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(Test6{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if !strings.Contains(ddl, ") ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin ROW_FORMAT=COMPRESSED AUTO_INCREMENT=1000000;") {
+		t.Fatal("error generate table options", ddl)
+	}
+
+	for _, driver := range []string{"postgres", "sqlite3"} {
+		dm, err = New(Config{
+			DB: DBConfig{Driver: driver},
+		})
+		if err != nil {
+			t.Fatal("error new maker", err)
+		}
+		err = dm.AddStruct(Test6{})
+		if err != nil {
+			t.Fatal("error add struct", err)
+		}
+		if _, err := dm.GenerateString(); err == nil || !strings.Contains(err.Error(), "unknown table option") {
+			t.Fatalf("error generate unknown table options of %s: %v", driver, err)
+		}
+	}
+}
+
+func TestGenerateTableConfig(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+		Tables: map[string]TableConfig{
+			"test1": {Skip: true},
+			"test6": {
+				Comment: "overridden",
+				Options: dialect.TableOptions{mysql.TableOptionRowFormat: "DYNAMIC"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(Test1{}, Test6{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if strings.Contains(ddl, "test1") {
+		t.Fatal("error skip table", ddl)
+	}
+	if !strings.Contains(ddl, ") ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin ROW_FORMAT=DYNAMIC AUTO_INCREMENT=1000000 COMMENT='overridden';") {
+		t.Fatal("error override table", ddl)
+	}
+
+	dm, err = New(Config{
+		DB:     DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+		Tables: map[string]TableConfig{"unknown": {Skip: true}},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(Test1{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	if _, err := dm.GenerateString(); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Fatal("error override unknown table", err)
+	}
+}
+
+func TestGeneratePartitioning(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(PartitionLog{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	expected := ") ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4\n" +
+		"PARTITION BY RANGE (TO_DAYS(created_at)) (\n" +
+		"    PARTITION `p2023` VALUES LESS THAN (TO_DAYS('2024-01-01')),\n" +
+		"    PARTITION `pmax` VALUES LESS THAN MAXVALUE\n" +
+		");\n"
+	if !strings.Contains(ddl, expected) {
+		t.Fatal("error generate partitioning", ddl)
+	}
+}
+
+func TestGenerateKeepsFile(t *testing.T) {
+	outFilePath := filepath.Join(t.TempDir(), "schema.sql")
+	if err := ioutil.WriteFile(outFilePath, []byte("-- schema\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dm, err := New(Config{
+		DB:          DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+		OutFilePath: outFilePath,
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(T2{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	if err := dm.Generate(); err == nil {
+		t.Fatal("error generate ddl of invalid struct")
+	}
+	if err := dm.GenerateDiffFromFile(outFilePath); err == nil {
+		t.Fatal("error generate diff of invalid struct")
+	}
+
+	b, err := ioutil.ReadFile(outFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "-- schema\n" {
+		t.Fatal("error keep ddl file", string(b))
+	}
+}
@@ -0,0 +1,940 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuoted
+	tokenString
+	tokenSymbol
+)
+
+// token is a lexical element of ddl
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// is reports whether t is the keyword or symbol s
+func (t token) is(s string) bool {
+	return (t.kind == tokenWord || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+// ddlColumn is a column parsed from ddl
+type ddlColumn struct {
+	name       string
+	typeName   string
+	charset    string
+	collate    string
+	null       bool
+	defaultVal string
+	onUpdate   string
+	generated  string
+	stored     bool
+	auto       bool
+	comment    string
+	extra      string
+	dialect    dialect.Dialect
+}
+
+func (c ddlColumn) Name() string {
+	return c.name
+}
+
+func (c ddlColumn) Comment() string {
+	return c.comment
+}
+
+// Generated reports whether the column is generated from an expression
+func (c ddlColumn) Generated() bool {
+	return c.generated != ""
+}
+
+// AutoIncrement reports whether the column is declared with AUTO_INCREMENT
+func (c ddlColumn) AutoIncrement() bool {
+	return c.auto
+}
+
+// DefaultSQL returns the default value written by ToSQL, and false if the column has no default
+func (c ddlColumn) DefaultSQL() (string, bool) {
+	return c.defaultVal, c.defaultVal != ""
+}
+
+// collation returns the sql type without the character set and the collation, and them
+func (c ddlColumn) collation() (string, string, string, error) {
+	return c.typeName, c.charset, c.collate, nil
+}
+
+// generatedSQL returns the generated column clause written by ToSQL, in which the expression is normalized if normalize is true
+func (c ddlColumn) generatedSQL(normalize bool) string {
+	d, ok := c.dialect.(dialect.Generator)
+	if !ok || c.generated == "" {
+		return ""
+	}
+	expr := c.generated
+	if normalize {
+		expr = normalizeExpr(expr)
+	}
+
+	return d.GeneratedSQL(expr, c.stored)
+}
+
+// ToSQL is convert parsed column to sql in the same format as column.ToSQL.
+func (c ddlColumn) ToSQL() (string, error) {
+	typeSQL := c.typeName
+	if d, ok := c.dialect.(dialect.Collator); ok && (c.charset != "" || c.collate != "") {
+		var err error
+		typeSQL, err = d.CollateSQL(typeSQL, c.charset, c.collate)
+		if err != nil {
+			return "", err
+		}
+	}
+	sql := []string{c.dialect.Quote(c.name), typeSQL}
+	if generated := c.generatedSQL(false); generated != "" {
+		sql = append(sql, generated)
+	}
+	if c.null {
+		sql = append(sql, "NULL")
+	} else {
+		sql = append(sql, "NOT NULL")
+	}
+	if c.defaultVal != "" {
+		sql = append(sql, "DEFAULT", c.defaultVal)
+	}
+	if d, ok := c.dialect.(dialect.OnUpdate); ok && c.onUpdate != "" {
+		sql = append(sql, d.OnUpdateSQL(c.onUpdate))
+	}
+	if c.auto {
+		sql = append(sql, c.dialect.AutoIncrement())
+	}
+	if commenter, ok := c.dialect.(dialect.Commenter); ok && c.comment != "" {
+		sql = append(sql, commenter.CommentSQL(c.comment))
+	}
+	if c.extra != "" {
+		sql = append(sql, c.extra)
+	}
+
+	return strings.Join(sql, " "), nil
+}
+
+// ddlTable is a table being parsed from ddl
+type ddlTable struct {
+	name         string
+	comment      string
+	primaryKey   dialect.PrimaryKey
+	foreignKeys  dialect.ForeignKeys
+	columns      []dialect.Column
+	indexes      dialect.Indexes
+	checks       dialect.Checks
+	options      dialect.TableOptions
+	partitioning dialect.Partitioning
+}
+
+// ddlPartitioning is the partitioning of a table parsed from ddl, which is kept in sql
+type ddlPartitioning struct {
+	sql string
+}
+
+func (p ddlPartitioning) Columns() []string {
+	return nil
+}
+
+func (p ddlPartitioning) ToSQL() string {
+	return p.sql
+}
+
+var (
+	integerWidthRe = regexp.MustCompile(`^(TINYINT|SMALLINT|MEDIUMINT|INT|INTEGER|BIGINT)\(\d+\)$`)
+	decimalRe      = regexp.MustCompile(`^(DECIMAL|NUMERIC|DEC|FIXED)(\((\d+)(,(\d+))?\))?$`)
+)
+
+// ParseDDL parses CREATE TABLE and CREATE INDEX statements, such as a file written by Generate or mysqldump --no-data,
+// into the same tables that are built from structs. Only the mysql dialect is supported.
+func (dm *DDLMaker) ParseDDL(r io.Reader) ([]dialect.Table, error) {
+	switch dm.Dialect.(type) {
+	case mysql.MySQL, *mysql.MySQL:
+	default:
+		return nil, fmt.Errorf("%T does not support parse ddl", dm.Dialect)
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "error read ddl")
+	}
+	src := string(b)
+
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "error tokenize ddl")
+	}
+
+	var ddlTables []*ddlTable
+	tableMap := make(map[string]*ddlTable)
+	for _, stmt := range splitTokens(tokens, ";") {
+		switch {
+		case len(stmt) > 2 && stmt[0].is("CREATE") && stmt[1].is("TABLE"):
+			t, err := parseCreateTable(src, stmt, dm.Dialect)
+			if err != nil {
+				return nil, err
+			}
+			ddlTables = append(ddlTables, t)
+			tableMap[t.name] = t
+		case len(stmt) > 2 && stmt[0].is("CREATE") && (stmt[1].is("INDEX") || stmt[2].is("INDEX")):
+			tableName, index, err := parseCreateIndex(stmt)
+			if err != nil {
+				return nil, err
+			}
+			t, ok := tableMap[tableName]
+			if !ok {
+				return nil, fmt.Errorf("index %s is created on unknown table %s", index.Name(), tableName)
+			}
+			t.indexes = append(t.indexes, index)
+		}
+	}
+
+	var tables []dialect.Table
+	for _, t := range ddlTables {
+		tables = append(tables, newTable(t.name, t.comment, t.primaryKey, t.foreignKeys, t.columns, removeImplicitIndexes(t), t.checks, t.options, t.partitioning, dm.Dialect))
+	}
+
+	return tables, nil
+}
+
+// GenerateDiffFromFile generate ddl file which migrates the tables written in ddlFilePath to the tables of dm
+func (dm *DDLMaker) GenerateDiffFromFile(ddlFilePath string) error {
+	log.Printf("start generate diff %s from %s \n", dm.config.OutFilePath, ddlFilePath)
+
+	var ddl bytes.Buffer
+	err := dm.GenerateDiffFromFileTo(&ddl, ddlFilePath)
+	if err != nil {
+		return err
+	}
+	if err := writeDDLFile(dm.config.OutFilePath, ddl.Bytes()); err != nil {
+		return err
+	}
+
+	log.Printf("done generate diff %s \n", dm.config.OutFilePath)
+
+	return nil
+}
+
+// GenerateDiffFromFileTo writes ddl which migrates the tables written in ddlFilePath to the tables of dm to w
+func (dm *DDLMaker) GenerateDiffFromFileTo(w io.Writer, ddlFilePath string) error {
+	if err := dm.parse(); err != nil {
+		return errors.Wrap(err, "error parse")
+	}
+
+	ddlFile, err := os.Open(ddlFilePath)
+	if err != nil {
+		return errors.Wrap(err, "error open ddl file")
+	}
+	defer ddlFile.Close()
+
+	prevTables, err := dm.ParseDDL(ddlFile)
+	if err != nil {
+		return errors.Wrap(err, "error parse ddl file")
+	}
+
+	err = dm.generateDiff(w, prevTables)
+	if err != nil {
+		return errors.Wrap(err, "error generate diff")
+	}
+
+	return nil
+}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	var conditional bool
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#', c == '-' && strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*!"):
+			// the content of a conditional comment such as /*!50100 WITH PARSER `ngram` */ is executed by MySQL
+			conditional = true
+			i += 3
+			for i < len(src) && '0' <= src[i] && src[i] <= '9' {
+				i++
+			}
+		case conditional && c == '*' && strings.HasPrefix(src[i:], "*/"):
+			conditional = false
+			i += 2
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at %d", i)
+			}
+			i += 2 + end + 2
+		case c == '`' || c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(src); j++ {
+				if src[j] == '\\' && c == '\'' {
+					j++
+					continue
+				}
+				if src[j] == c {
+					if j+1 < len(src) && src[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated quote at %d", i)
+			}
+			t := token{kind: tokenString, text: src[i : j+1], start: i, end: j + 1}
+			if c != '\'' {
+				t.kind = tokenQuoted
+				t.text = strings.Replace(src[i+1:j], string([]byte{c, c}), string(c), -1)
+			}
+			tokens = append(tokens, t)
+			i = j + 1
+		case isWordByte(c) || (c == '-' && i+1 < len(src) && '0' <= src[i+1] && src[i+1] <= '9'):
+			j := i + 1
+			for j < len(src) && isWordByte(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: src[i:j], start: i, end: j})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenSymbol, text: src[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func isWordByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '$' || c == '.' || c >= 0x80
+}
+
+// splitTokens splits tokens by sep which is not enclosed in parentheses
+func splitTokens(tokens []token, sep string) [][]token {
+	var groups [][]token
+	var group []token
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case depth == 0 && t.is(sep):
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+			group = nil
+			continue
+		}
+		group = append(group, t)
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// closeParen returns the position of the parenthesis which closes tokens[open]
+func closeParen(tokens []token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// rawSQL returns the source text of tokens with whitespaces collapsed
+func rawSQL(src string, tokens []token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(src[tokens[0].start:tokens[len(tokens)-1].end]), " ")
+}
+
+func parseCreateTable(src string, stmt []token, d dialect.Dialect) (*ddlTable, error) {
+	pos := 2
+	if len(stmt) > pos+2 && stmt[pos].is("IF") && stmt[pos+1].is("NOT") && stmt[pos+2].is("EXISTS") {
+		pos += 3
+	}
+	if pos >= len(stmt) {
+		return nil, fmt.Errorf("table name is not found: %s", rawSQL(src, stmt))
+	}
+	name, pos := tableIdent(stmt, pos)
+	t := &ddlTable{name: name}
+
+	if pos >= len(stmt) || !stmt[pos].is("(") {
+		return nil, fmt.Errorf("table %s has no definitions", t.name)
+	}
+	end := closeParen(stmt, pos)
+	if end < 0 {
+		return nil, fmt.Errorf("table %s has unclosed definitions", t.name)
+	}
+
+	for _, def := range splitTokens(stmt[pos+1:end], ",") {
+		if err := t.parseDefinition(src, def, d); err != nil {
+			return nil, errors.Wrapf(err, "error parse table %s", t.name)
+		}
+	}
+
+	// table options such as ENGINE=InnoDB COMMENT='...', and the partitioning which follows them
+	for pos = end + 1; pos < len(stmt); pos++ {
+		if stmt[pos].is("PARTITION") {
+			t.partitioning = ddlPartitioning{sql: rawSQL(src, stmt[pos:])}
+			break
+		}
+
+		name := tableOptionName(stmt, pos)
+		if name == "" {
+			continue
+		}
+		if stmt[pos].is("CHARACTER") {
+			pos++
+		}
+		if pos+1 < len(stmt) && stmt[pos+1].is("=") {
+			pos++
+		}
+		if pos+1 >= len(stmt) {
+			return nil, fmt.Errorf("value of table option %s is not found in table %s", name, t.name)
+		}
+		pos++
+
+		if name == "COMMENT" {
+			if stmt[pos].kind == tokenString {
+				t.comment = unquoteString(stmt[pos].text)
+			}
+			continue
+		}
+		value := stmt[pos].text
+		if stmt[pos].kind == tokenString {
+			value = unquoteString(value)
+		}
+		if t.options == nil {
+			t.options = make(dialect.TableOptions)
+		}
+		t.options[name] = value
+	}
+
+	return t, nil
+}
+
+// tableOptionName returns the name of the table option at pos, such as mysql.TableOptionEngine or COMMENT,
+// which is empty for the options which are not read.
+func tableOptionName(stmt []token, pos int) string {
+	switch {
+	case stmt[pos].is("COMMENT"):
+		return "COMMENT"
+	case stmt[pos].is("ENGINE"):
+		return mysql.TableOptionEngine
+	case stmt[pos].is("CHARSET"), stmt[pos].is("CHARACTER") && pos+1 < len(stmt) && stmt[pos+1].is("SET"):
+		return mysql.TableOptionCharset
+	case stmt[pos].is("COLLATE"):
+		return mysql.TableOptionCollate
+	case stmt[pos].is("ROW_FORMAT"):
+		return mysql.TableOptionRowFormat
+	case stmt[pos].is("AUTO_INCREMENT"):
+		return mysql.TableOptionAutoIncrement
+	default:
+		return ""
+	}
+}
+
+func (t *ddlTable) parseDefinition(src string, def []token, d dialect.Dialect) error {
+	var constraint string
+	if def[0].is("CONSTRAINT") {
+		if len(def) > 1 && !def[1].is("PRIMARY") && !def[1].is("UNIQUE") && !def[1].is("FOREIGN") && !def[1].is("CHECK") {
+			constraint = def[1].text
+			def = def[2:]
+		} else {
+			def = def[1:]
+		}
+	}
+	if len(def) == 0 {
+		return fmt.Errorf("empty definition")
+	}
+
+	switch {
+	case def[0].is("PRIMARY"):
+		columns, _, err := parseKeyColumns(def, 1)
+		if err != nil {
+			return err
+		}
+		t.primaryKey = mysql.AddPrimaryKey(columns...)
+	case def[0].is("FOREIGN"):
+		fk, err := parseForeignKey(def, constraint)
+		if err != nil {
+			return err
+		}
+		t.foreignKeys = append(t.foreignKeys, fk)
+	case def[0].is("CHECK"):
+		check, err := parseCheck(src, def, constraint)
+		if err != nil {
+			return err
+		}
+		t.checks = append(t.checks, check)
+	case def[0].is("INDEX"), def[0].is("KEY"), def[0].is("UNIQUE"), def[0].is("FULLTEXT"), def[0].is("SPATIAL"):
+		index, err := parseIndex(def, constraint)
+		if err != nil {
+			return err
+		}
+		t.indexes = append(t.indexes, index)
+	default:
+		column, err := parseColumn(src, def, d)
+		if err != nil {
+			return err
+		}
+		t.columns = append(t.columns, column)
+	}
+
+	return nil
+}
+
+// parseCheck parses CHECK (expr) [[NOT] ENFORCED]
+func parseCheck(src string, def []token, name string) (dialect.Check, error) {
+	if len(def) < 2 || !def[1].is("(") {
+		return nil, fmt.Errorf("expression of check %s is not found", name)
+	}
+	end := closeParen(def, 1)
+	if end < 0 {
+		return nil, fmt.Errorf("expression of check %s is not closed", name)
+	}
+
+	return mysql.AddCheck(name, rawSQL(src, def[2:end])), nil
+}
+
+// parseKeyColumns parses the column list which starts after the keyword at pos,
+// and returns the columns and the position next to the list.
+func parseKeyColumns(def []token, pos int) ([]string, int, error) {
+	for pos < len(def) && !def[pos].is("(") {
+		pos++
+	}
+	if pos >= len(def) {
+		return nil, pos, fmt.Errorf("column list is not found")
+	}
+	end := closeParen(def, pos)
+	if end < 0 {
+		return nil, pos, fmt.Errorf("column list is not closed")
+	}
+
+	var columns []string
+	for _, c := range splitTokens(def[pos+1:end], ",") {
+		// ignore key part length and order such as `name`(10) DESC
+		columns = append(columns, c[0].text)
+	}
+	if len(columns) == 0 {
+		return nil, pos, fmt.Errorf("column list is empty")
+	}
+
+	return columns, end + 1, nil
+}
+
+func parseIndex(def []token, constraint string) (dialect.Index, error) {
+	kind := strings.ToUpper(def[0].text)
+	pos := 1
+	for pos < len(def) && (def[pos].is("INDEX") || def[pos].is("KEY")) {
+		pos++
+	}
+	name := constraint
+	if pos < len(def) && !def[pos].is("(") {
+		name = def[pos].text
+		pos++
+	}
+
+	columns, pos, err := parseKeyColumns(def, pos)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		// MySQL names an unnamed index after its first column
+		name = columns[0]
+	}
+
+	switch kind {
+	case "UNIQUE":
+		return mysql.AddUniqueIndex(name, columns...), nil
+	case "FULLTEXT":
+		index := mysql.AddFullTextIndex(name, columns...)
+		for ; pos+2 < len(def); pos++ {
+			if def[pos].is("WITH") && def[pos+1].is("PARSER") {
+				index = index.WithParser(def[pos+2].text)
+			}
+		}
+		return index, nil
+	case "SPATIAL":
+		return mysql.AddSpatialIndex(name, columns...), nil
+	default:
+		return mysql.AddIndex(name, columns...), nil
+	}
+}
+
+func parseCreateIndex(stmt []token) (string, dialect.Index, error) {
+	// CREATE [UNIQUE|FULLTEXT|SPATIAL] INDEX name ON table (columns)
+	def := stmt[1:]
+	pos := 0
+	for pos < len(def) && !def[pos].is("ON") {
+		pos++
+	}
+	if pos+1 >= len(def) {
+		return "", nil, fmt.Errorf("table of index is not found")
+	}
+	tableName, next := tableIdent(def, pos+1)
+
+	var indexDef []token
+	indexDef = append(indexDef, def[:pos]...)
+	indexDef = append(indexDef, def[next:]...)
+	index, err := parseIndex(indexDef, "")
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "error parse index of table %s", tableName)
+	}
+
+	return tableName, index, nil
+}
+
+func parseForeignKey(def []token, constraint string) (dialect.ForeignKey, error) {
+	foreignColumns, pos, err := parseKeyColumns(def, 2)
+	if err != nil {
+		return nil, err
+	}
+	if pos+1 >= len(def) || !def[pos].is("REFERENCES") {
+		return nil, fmt.Errorf("references of foreign key is not found")
+	}
+	referenceTableName, next := tableIdent(def, pos+1)
+	referenceColumns, pos, err := parseKeyColumns(def, next)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []mysql.ForeignKeyOption
+	if constraint != "" {
+		options = append(options, mysql.WithForeignKeyName(constraint))
+	}
+	for ; pos+2 < len(def); pos++ {
+		if !def[pos].is("ON") {
+			continue
+		}
+		action := def[pos+2].text
+		if pos+3 < len(def) && (def[pos+2].is("SET") || def[pos+2].is("NO")) {
+			action += " " + def[pos+3].text
+		}
+		option := mysql.ForeignKeyOptionType(strings.ToUpper(action))
+		switch {
+		case def[pos+1].is("DELETE"):
+			options = append(options, mysql.WithDeleteForeignKeyOption(option))
+		case def[pos+1].is("UPDATE"):
+			options = append(options, mysql.WithUpdateForeignKeyOption(option))
+		}
+	}
+
+	return mysql.AddForeignKey(foreignColumns, referenceColumns, referenceTableName, options...), nil
+}
+
+func parseColumn(src string, def []token, d dialect.Dialect) (ddlColumn, error) {
+	column := ddlColumn{
+		name:    def[0].text,
+		null:    true,
+		dialect: d,
+	}
+	if len(def) < 2 {
+		return column, fmt.Errorf("type of column %s is not found", column.name)
+	}
+
+	// type name: words, arguments in parentheses, unsigned and zerofill
+	pos := 1
+	typeName := strings.ToUpper(def[pos].text)
+	pos++
+	if pos < len(def) && def[pos].is("(") {
+		end := closeParen(def, pos)
+		if end < 0 {
+			return column, fmt.Errorf("type of column %s is not closed", column.name)
+		}
+		for _, t := range def[pos : end+1] {
+			typeName += t.text
+		}
+		pos = end + 1
+	}
+	if typeName == "DOUBLE" && pos < len(def) && def[pos].is("PRECISION") {
+		pos++
+	}
+	var unsigned bool
+	for pos < len(def) && (def[pos].is("UNSIGNED") || def[pos].is("ZEROFILL")) {
+		if def[pos].is("UNSIGNED") {
+			unsigned = true
+		}
+		pos++
+	}
+	column.typeName = normalizeType(typeName)
+	if unsigned {
+		column.typeName += " unsigned"
+	}
+
+	// attributes
+	var extra []string
+	extraStart := -1
+	for pos < len(def) {
+		if extraStart >= 0 && isColumnAttribute(def, pos) {
+			extra = append(extra, rawSQL(src, def[extraStart:pos]))
+			extraStart = -1
+		}
+		switch {
+		case def[pos].is("NOT") && pos+1 < len(def) && def[pos+1].is("NULL"):
+			column.null = false
+			pos += 2
+		case def[pos].is("NULL"):
+			pos++
+		case def[pos].is("AUTO_INCREMENT"):
+			column.auto = true
+			pos++
+		case isCharsetAttribute(def, pos):
+			if def[pos].is("CHARACTER") {
+				pos++
+			}
+			column.charset = def[pos+1].text
+			pos += 2
+		case def[pos].is("COLLATE") && pos+1 < len(def):
+			column.collate = def[pos+1].text
+			pos += 2
+		case def[pos].is("ON") && pos+2 < len(def) && def[pos+1].is("UPDATE"):
+			end := pos + 3
+			if end < len(def) && def[end].is("(") {
+				// function call such as CURRENT_TIMESTAMP(6)
+				end = closeParen(def, end) + 1
+			}
+			if end <= pos+2 {
+				return column, fmt.Errorf("on update of column %s is not closed", column.name)
+			}
+			column.onUpdate = rawSQL(src, def[pos+2:end])
+			pos = end
+		case isGeneratedAttribute(def, pos):
+			if def[pos].is("GENERATED") {
+				pos += 2
+			}
+			end := closeParen(def, pos+1)
+			if end < 0 {
+				return column, fmt.Errorf("generated expression of column %s is not closed", column.name)
+			}
+			column.generated = rawSQL(src, def[pos+2:end])
+			pos = end + 1
+			if pos < len(def) && (def[pos].is("STORED") || def[pos].is("VIRTUAL")) {
+				column.stored = def[pos].is("STORED")
+				pos++
+			}
+		case def[pos].is("COMMENT") && pos+1 < len(def) && def[pos+1].kind == tokenString:
+			column.comment = unquoteString(def[pos+1].text)
+			pos += 2
+		case def[pos].is("DEFAULT") && pos+1 < len(def):
+			end := pos + 2
+			if def[pos+1].is("(") {
+				end = closeParen(def, pos+1) + 1
+			} else if end < len(def) && def[end].is("(") {
+				// function call such as CURRENT_TIMESTAMP(6)
+				end = closeParen(def, end) + 1
+			}
+			if end <= pos+1 {
+				return column, fmt.Errorf("default of column %s is not closed", column.name)
+			}
+			column.defaultVal = normalizeDefault(rawSQL(src, def[pos+1:end]))
+			pos = end
+		default:
+			if extraStart < 0 {
+				extraStart = pos
+			}
+			if def[pos].is("(") {
+				pos = closeParen(def, pos)
+				if pos < 0 {
+					return column, fmt.Errorf("attribute of column %s is not closed", column.name)
+				}
+			}
+			pos++
+		}
+	}
+	if extraStart >= 0 {
+		extra = append(extra, rawSQL(src, def[extraStart:]))
+	}
+	if column.null && strings.EqualFold(column.defaultVal, "NULL") {
+		// DEFAULT NULL is implied by a nullable column
+		column.defaultVal = ""
+	}
+	column.extra = strings.Join(extra, " ")
+
+	return column, nil
+}
+
+// isColumnAttribute reports whether def[pos] starts an attribute which is parsed into ddlColumn
+func isColumnAttribute(def []token, pos int) bool {
+	return def[pos].is("NOT") && pos+1 < len(def) && def[pos+1].is("NULL") ||
+		def[pos].is("NULL") || def[pos].is("AUTO_INCREMENT") || def[pos].is("DEFAULT") ||
+		def[pos].is("ON") && pos+1 < len(def) && def[pos+1].is("UPDATE") ||
+		def[pos].is("COMMENT") && pos+1 < len(def) && def[pos+1].kind == tokenString ||
+		isGeneratedAttribute(def, pos) || isCharsetAttribute(def, pos) ||
+		def[pos].is("COLLATE") && pos+1 < len(def)
+}
+
+// isCharsetAttribute reports whether def[pos] starts CHARACTER SET name or CHARSET name
+func isCharsetAttribute(def []token, pos int) bool {
+	return def[pos].is("CHARACTER") && pos+2 < len(def) && def[pos+1].is("SET") ||
+		def[pos].is("CHARSET") && pos+1 < len(def)
+}
+
+// isGeneratedAttribute reports whether def[pos] starts [GENERATED ALWAYS] AS (expr)
+func isGeneratedAttribute(def []token, pos int) bool {
+	if def[pos].is("GENERATED") && pos+1 < len(def) && def[pos+1].is("ALWAYS") {
+		pos += 2
+	}
+	return pos+1 < len(def) && def[pos].is("AS") && def[pos+1].is("(")
+}
+
+// unquoteString returns the value of a quoted mysql string literal, in which a doubled quote is a single quote
+func unquoteString(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+
+	var b strings.Builder
+	s = s[1 : len(s)-1]
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(s[i])
+			}
+		case s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+			b.WriteByte('\'')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// normalizeType converts a mysql type name to the one written by mysql.ToSQL
+func normalizeType(typeName string) string {
+	switch typeName {
+	case "INT":
+		return "INTEGER"
+	case "BOOL", "BOOLEAN":
+		return "TINYINT(1)"
+	case "REAL":
+		return "DOUBLE"
+	case "TINYINT(1)":
+		return typeName
+	}
+
+	// display width of integer types is deprecated and not written by mysql.ToSQL
+	if integerWidthRe.MatchString(typeName) {
+		return normalizeType(typeName[:strings.Index(typeName, "(")])
+	}
+
+	// DECIMAL is DECIMAL(10,0) and DECIMAL(M) is DECIMAL(M,0)
+	if m := decimalRe.FindStringSubmatch(typeName); m != nil {
+		precision, scale := m[3], m[5]
+		if precision == "" {
+			precision = "10"
+		}
+		if scale == "" {
+			scale = "0"
+		}
+		return fmt.Sprintf("DECIMAL(%s,%s)", precision, scale)
+	}
+
+	return typeName
+}
+
+// normalizeDefault converts a default value written by mysqldump such as '0' to the one written in a struct tag
+func normalizeDefault(defaultVal string) string {
+	if len(defaultVal) > 1 && strings.HasPrefix(defaultVal, "'") && strings.HasSuffix(defaultVal, "'") {
+		if unquoted := defaultVal[1 : len(defaultVal)-1]; numericDefaultRe.MatchString(unquoted) {
+			return unquoted
+		}
+	}
+
+	return defaultVal
+}
+
+// tableIdent returns the table name at pos which may be qualified by its database,
+// and the position next to it.
+func tableIdent(tokens []token, pos int) (string, int) {
+	name := tokens[pos].text
+	pos++
+	for pos+1 < len(tokens) && tokens[pos].text == "." {
+		name = tokens[pos+1].text
+		pos += 2
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 && tokens[pos-1].kind == tokenWord {
+		name = name[i+1:]
+	}
+
+	return name, pos
+}
+
+// removeImplicitIndexes removes the indexes which MySQL creates implicitly for foreign keys
+func removeImplicitIndexes(t *ddlTable) dialect.Indexes {
+	var indexes dialect.Indexes
+	for _, index := range t.indexes {
+		if _, ok := index.(mysql.Index); ok && isImplicitIndex(index, t.foreignKeys) {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+func isImplicitIndex(index dialect.Index, foreignKeys dialect.ForeignKeys) bool {
+	for _, fk := range foreignKeys {
+		if strings.Join(index.Columns(), ",") != strings.Join(fk.ForeignColumns(), ",") {
+			continue
+		}
+		if index.Name() == fk.Name() || index.Name() == fk.ForeignColumns()[0] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,240 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+const mysqldumpDDL = "-- MySQL dump 10.13\n" +
+	"/* comment */\n" +
+	"/*!40101 SET NAMES utf8mb4 */;\n" +
+	"DROP TABLE IF EXISTS `bookmark`;\n" +
+	"CREATE TABLE `bookmark` (\n" +
+	"  `id` int(11) NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_id` bigint(20) unsigned NOT NULL,\n" +
+	"  `entry_id` int(11) NOT NULL,\n" +
+	"  `public` tinyint(1) NOT NULL DEFAULT '0',\n" +
+	"  `price` decimal(12,2) NOT NULL,\n" +
+	"  `rate` decimal NOT NULL,\n" +
+	"  `status` enum('draft','it''s') NOT NULL DEFAULT 'draft',\n" +
+	"  `memo` varchar(99) COLLATE utf8mb4_bin DEFAULT NULL COMMENT 'it''s memo',\n" +
+	"  `created_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),\n" +
+	"  `updated_at` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),\n" +
+	"  `token` varchar(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT (uuid()),\n" +
+	"  `total` decimal(13,2) GENERATED ALWAYS AS ((`price` * 1.1)) STORED,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `user_id_entry_id` (`user_id`,`entry_id`),\n" +
+	"  KEY `entry_id` (`entry_id`),\n" +
+	"  FULLTEXT KEY `memo_idx` (`memo`) /*!50100 WITH PARSER `ngram` */ ,\n" +
+	"  CONSTRAINT `bookmark_ibfk_1` FOREIGN KEY (`entry_id`) REFERENCES `entry` (`id`) ON DELETE CASCADE,\n" +
+	"  CONSTRAINT `price_positive` CHECK ((`price` >= 0)) /*!80016 NOT ENFORCED */\n" +
+	") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COMMENT='bookmarks of \\'entry\\'';\n"
+
+func TestParseDDL(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables, err := dm.ParseDDL(strings.NewReader(mysqldumpDDL))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+	if len(tables) != 1 {
+		t.Fatal("error parse tables", len(tables))
+	}
+
+	table := tables[0]
+	if table.Name() != "`bookmark`" {
+		t.Fatal("error parse table name", table.Name())
+	}
+
+	columns := []string{
+		"`id` INTEGER NOT NULL AUTO_INCREMENT",
+		"`user_id` BIGINT unsigned NOT NULL",
+		"`entry_id` INTEGER NOT NULL",
+		"`public` TINYINT(1) NOT NULL DEFAULT 0",
+		"`price` DECIMAL(12,2) NOT NULL",
+		"`rate` DECIMAL(10,0) NOT NULL",
+		"`status` ENUM('draft','it''s') NOT NULL DEFAULT 'draft'",
+		"`memo` VARCHAR(99) COLLATE utf8mb4_bin NULL COMMENT 'it''s memo'",
+		"`created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+		"`updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)",
+		"`token` VARCHAR(36) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT (uuid())",
+		"`total` DECIMAL(13,2) GENERATED ALWAYS AS ((`price` * 1.1)) STORED NULL",
+	}
+	if len(table.Columns()) != len(columns) {
+		t.Fatal("error parse columns", len(table.Columns()))
+	}
+	for i, c := range table.Columns() {
+		if sql, _ := c.ToSQL(); sql != columns[i] {
+			t.Fatalf("error parse column. result: %s expected: %s", sql, columns[i])
+		}
+	}
+
+	if table.Comment() != "bookmarks of 'entry'" {
+		t.Fatal("error parse table comment", table.Comment())
+	}
+	if c := table.Columns()[7]; c.Comment() != "it's memo" {
+		t.Fatal("error parse column comment", c.Comment())
+	}
+
+	if table.PrimaryKey().ToSQL() != "PRIMARY KEY (`id`)" {
+		t.Fatal("error parse pk", table.PrimaryKey().ToSQL())
+	}
+
+	// the implicit index of the foreign key is removed
+	indexes := []string{
+		"UNIQUE `user_id_entry_id` (`user_id`, `entry_id`)",
+		"FULLTEXT `memo_idx` (`memo`) WITH PARSER `ngram`",
+	}
+	if len(table.Indexes()) != len(indexes) {
+		t.Fatal("error parse indexes", len(table.Indexes()))
+	}
+	for i, idx := range table.Indexes() {
+		if idx.ToSQL() != indexes[i] {
+			t.Fatalf("error parse index. result: %s expected: %s", idx.ToSQL(), indexes[i])
+		}
+	}
+
+	if len(table.ForeignKeys()) != 1 {
+		t.Fatal("error parse fk", len(table.ForeignKeys()))
+	}
+	if fk := table.ForeignKeys()[0]; fk.ToSQL() != "CONSTRAINT `bookmark_ibfk_1` FOREIGN KEY (`entry_id`) REFERENCES `entry` (`id`) ON DELETE CASCADE" {
+		t.Fatal("error parse fk", fk.ToSQL())
+	}
+
+	if len(table.Checks()) != 1 {
+		t.Fatal("error parse check", len(table.Checks()))
+	}
+	if check := table.Checks()[0]; check.ToSQL() != "CONSTRAINT `price_positive` CHECK ((`price` >= 0))" {
+		t.Fatal("error parse check", check.ToSQL())
+	}
+
+	options := dialect.TableOptions{
+		mysql.TableOptionEngine:        "InnoDB",
+		mysql.TableOptionAutoIncrement: "3",
+		mysql.TableOptionCharset:       "utf8mb4",
+	}
+	if !reflect.DeepEqual(table.TableOptions(), options) {
+		t.Fatal("error parse table options", table.TableOptions())
+	}
+	if table.Partitioning() != nil {
+		t.Fatal("error parse partitioning", table.Partitioning())
+	}
+}
+
+func TestParsePartitionedDDL(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables, err := dm.ParseDDL(strings.NewReader("CREATE TABLE `log` (\n" +
+		"  `id` bigint NOT NULL,\n" +
+		"  `created_at` datetime NOT NULL,\n" +
+		"  PRIMARY KEY (`id`,`created_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin ROW_FORMAT=COMPRESSED\n" +
+		"/*!50100 PARTITION BY RANGE (year(`created_at`))\n" +
+		"(PARTITION p2020 VALUES LESS THAN (2021) ENGINE = InnoDB,\n" +
+		" PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */;\n"))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+
+	table := tables[0]
+	options := dialect.TableOptions{
+		mysql.TableOptionEngine:    "InnoDB",
+		mysql.TableOptionCharset:   "utf8mb4",
+		mysql.TableOptionCollate:   "utf8mb4_bin",
+		mysql.TableOptionRowFormat: "COMPRESSED",
+	}
+	if !reflect.DeepEqual(table.TableOptions(), options) {
+		t.Fatal("error parse table options", table.TableOptions())
+	}
+	partitioning := "PARTITION BY RANGE (year(`created_at`)) (PARTITION p2020 VALUES LESS THAN (2021) ENGINE = InnoDB, PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB)"
+	if table.Partitioning() == nil || table.Partitioning().ToSQL() != partitioning {
+		t.Fatal("error parse partitioning", table.Partitioning())
+	}
+}
+
+func TestParseGeneratedDDL(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T1{}, &Test2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generate(&ddl)
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	tables, err := dm.ParseDDL(bytes.NewReader(ddl.Bytes()))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+
+	// the generated ddl is parsed back into the same tables
+	parsed := &DDLMaker{Dialect: dm.Dialect, Tables: tables}
+	var reDDL bytes.Buffer
+	err = parsed.generate(&reDDL)
+	if err != nil {
+		t.Fatal("error generate parsed ddl", err)
+	}
+	if reDDL.String() != ddl.String() {
+		t.Fatalf("generatedDDL: %s \n parsedDDL: %s \n", ddl.String(), reDDL.String())
+	}
+
+	var diff bytes.Buffer
+	err = dm.generateDiff(&diff, tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	m := mysql.MySQL{}
+	if diff.String() != m.HeaderTemplate()+m.FooterTemplate() {
+		t.Fatal("error diff of parsed ddl", diff.String())
+	}
+}
+
+func TestParseInvalidDDL(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ddl := range []string{
+		"CREATE TABLE `user` (`id` INTEGER NOT NULL, KEY ());",
+		"CREATE TABLE `user` (`id` INTEGER NOT NULL, UNIQUE KEY `id_idx` (,));",
+		"CREATE TABLE `user` (`id` INTEGER NOT NULL); CREATE INDEX `id_idx` ON `user` ();",
+	} {
+		if _, err := dm.ParseDDL(strings.NewReader(ddl)); err == nil || !strings.Contains(err.Error(), "column list is empty") {
+			t.Errorf("error parse %s: %v", ddl, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ddlmaker
+
+import (
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/pkg/errors"
+	"github.com/serenize/snaker"
+)
+
+// Field is a struct field described without reflection, such as the fields loaded by the static package
+type Field struct {
+	// Name is the name of the field
+	Name string
+	// TypeName is the name of the field type in the format of reflect.Type.String(), such as "*time.Time"
+	TypeName string
+	// Tag is the value of the ddl tag
+	Tag string
+	// SQLType is the sql type returned by DDLType() of the field type
+	SQLType string
+	// Values are the values returned by EnumValues() of the field type
+	Values []string
+}
+
+// TableDef is a table described without reflection, whose fields are the results of the table methods such as PrimaryKey()
+type TableDef struct {
+	// Name is the table name, which is converted to snake case
+	Name         string
+	Comment      string
+	PrimaryKey   dialect.PrimaryKey
+	ForeignKeys  dialect.ForeignKeys
+	Columns      []dialect.Column
+	Indexes      dialect.Indexes
+	Checks       dialect.Checks
+	Options      dialect.TableOptions
+	Partitioning dialect.Partitioning
+}
+
+// FlattenTag returns the column name prefix and true when the field of the tag is flattened,
+// which is an embedded struct or a struct field with prefix tag.
+// isStruct must be false for a struct which is a single column, such as time.Time, sql.NullString or a type with DDLType().
+func FlattenTag(tag string, anonymous, isStruct bool) (string, bool, error) {
+	return flattenTag(tag, anonymous, isStruct)
+}
+
+// NewColumn returns the column of field, whose name is prefixed.
+// It returns ErrIgnoreField if the field has the ignore tag.
+func NewColumn(field Field, prefix string, d dialect.Dialect) (dialect.Column, error) {
+	specs, err := parseTag(field.Tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parse tag")
+	}
+	if _, ok := specs[IGNORETAG]; ok {
+		return nil, ErrIgnoreField
+	}
+
+	column := newColumn(prefix+snaker.CamelToSnake(field.Name), field.TypeName, field.Tag, d)
+	column.sqlType = field.SQLType
+	column.values = field.Values
+
+	return column, nil
+}
+
+// NewTable returns the table of def, which can be added by AddTables
+func NewTable(def TableDef, d dialect.Dialect) dialect.Table {
+	return newTable(snaker.CamelToSnake(def.Name), def.Comment, def.PrimaryKey, def.ForeignKeys, def.Columns,
+		def.Indexes, def.Checks, def.Options, def.Partitioning, d)
+}
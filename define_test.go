@@ -0,0 +1,61 @@
+package ddlmaker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+func TestAddTables(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+
+	id, err := NewColumn(Field{Name: "ID", TypeName: "uint64", Tag: "auto"}, "", dm.Dialect)
+	if err != nil {
+		t.Fatal("error new column", err)
+	}
+	if _, err := NewColumn(Field{Name: "Memo", TypeName: "string", Tag: "-"}, "", dm.Dialect); err != ErrIgnoreField {
+		t.Fatal("error new column of ignored field", err)
+	}
+	err = dm.AddTables(NewTable(TableDef{
+		Name:         "AccessLog",
+		PrimaryKey:   mysql.AddPrimaryKey("id"),
+		Columns:      []dialect.Column{id},
+		Partitioning: mysql.PartitionByHash("id", 4),
+	}, dm.Dialect))
+	if err != nil {
+		t.Fatal("error add tables", err)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if !strings.Contains(ddl, "CREATE TABLE `access_log` (\n    `id` BIGINT unsigned NOT NULL AUTO_INCREMENT,") {
+		t.Fatal("error generate added table", ddl)
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "sqlite3"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddTables(NewTable(TableDef{
+		Name:         "access_log",
+		PrimaryKey:   mysql.AddPrimaryKey("id"),
+		Partitioning: mysql.PartitionByHash("id", 4),
+	}, dm.Dialect))
+	if err != nil {
+		t.Fatal("error add tables", err)
+	}
+	if _, err := dm.GenerateString(); err == nil || !strings.Contains(err.Error(), "does not support partitioning") {
+		t.Fatal("error generate partitioned table of sqlite", err)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"sort"
 
 	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
+	"github.com/kayac/ddl-maker/dialect/sqlite"
+	"github.com/kayac/ddl-maker/dialect/typemap"
 )
 
 // Dialect XXX
@@ -12,25 +15,166 @@ type Dialect interface {
 	HeaderTemplate() string
 	FooterTemplate() string
 	TableTemplate() string
-	ToSQL(typeName string, size uint64) string
+	ToSQL(typeName string, size uint64) (string, error)
 	Quote(string) string
 	AutoIncrement() string
 }
 
+// Migrator is a Dialect which can render the migration between two schemas
+type Migrator interface {
+	AlterTableTemplate() string
+	DropTableTemplate() string
+}
+
+// ForwardReference is a Dialect which allows a foreign key to reference a table created later,
+// such as MySQL with foreign_key_checks=0. Foreign keys in a cycle are kept in CREATE TABLE for such dialects,
+// otherwise they are added by ALTER TABLE after all tables are created.
+type ForwardReference interface {
+	AllowForwardReference() bool
+}
+
+// SchemaIndexName is a Dialect whose index names are unique in the schema, not in each table, such as PostgreSQL and SQLite
+type SchemaIndexName interface {
+	SchemaIndexName() bool
+}
+
+// Partitioner is a Dialect which can partition a table by Table.Partitioning
+type Partitioner interface {
+	SupportPartitioning() bool
+}
+
+// Commenter is a Dialect which writes the comment of a column in its definition
+type Commenter interface {
+	CommentSQL(comment string) string
+}
+
+// StringQuoter is a Dialect which writes string literals with its own escaping
+type StringQuoter interface {
+	QuoteString(s string) string
+}
+
+// OnUpdate is a Dialect which can set a column on every update of a row, such as ON UPDATE CURRENT_TIMESTAMP
+type OnUpdate interface {
+	OnUpdateSQL(expr string) string
+}
+
+// Generator is a Dialect which can declare a column generated from an expression
+type Generator interface {
+	GeneratedSQL(expr string, stored bool) string
+}
+
+// Collator is a Dialect which can set the character set or the collation of a column.
+// It returns typeSQL with them, and an error if one of them is not supported.
+type Collator interface {
+	CollateSQL(typeSQL, charset, collate string) (string, error)
+}
+
+// Decimal is a Dialect which can declare the precision and scale of a fixed-point type, such as DECIMAL(12,2)
+type Decimal interface {
+	DecimalToSQL(typeName string, precision, scale uint64) (string, error)
+}
+
+// Enum is a Dialect which can restrict a column to a fixed set of values, such as ENUM('a','b') or a CHECK constraint.
+// typeName is "enum", "set" or the type name of the field.
+type Enum interface {
+	EnumToSQL(column, typeName string, size uint64, values []string) (string, error)
+}
+
+// EnumCheck is an Enum which restricts the values by a CHECK constraint written after the sql type, such as PostgreSQL.
+// EnumTypeSQL returns the sql type without the constraint.
+type EnumCheck interface {
+	Enum
+	EnumTypeSQL(typeName string, size uint64) (string, error)
+}
+
+// TypeRegistry is a Dialect which can map Go types to sql types in addition to the built-in types
+type TypeRegistry interface {
+	RegisterType(t interface{}, f typemap.TypeFunc) error
+}
+
 // Table XXX
 type Table interface {
 	Name() string
+	Comment() string
 	PrimaryKey() PrimaryKey
 	ForeignKeys() ForeignKeys
 	Indexes() Indexes
+	Checks() Checks
+	TableOptions() TableOptions
+	Partitioning() Partitioning
 	Columns() []Column
 	Dialect() Dialect
 }
 
+// TableOptions is the options of a table, such as mysql.TableOptionRowFormat, which override the options of the Dialect.
+// The keys are the option names of the dialect.
+type TableOptions map[string]string
+
+// Partitioning is the partitioning of a table, such as mysql.PartitionByRange
+type Partitioning interface {
+	Columns() []string
+	ToSQL() string
+}
+
+// TableDiff is the difference of a table between two schemas
+type TableDiff interface {
+	Name() string
+	AddColumns() []Column
+	ModifyColumns() []Column
+	DropColumns() []Column
+	AddIndexes() Indexes
+	DropIndexes() Indexes
+	AddForeignKeys() ForeignKeys
+	DropForeignKeys() ForeignKeys
+	// AddPrimaryKey and DropPrimaryKey are nil unless the primary key is changed
+	AddPrimaryKey() PrimaryKey
+	DropPrimaryKey() PrimaryKey
+	AddChecks() Checks
+	DropChecks() Checks
+	// ModifyEnumColumns are the columns whose enum values are changed, which are also in ModifyColumns.
+	// They are used by the dialects which restrict the values by a CHECK constraint.
+	ModifyEnumColumns() []EnumColumn
+	Dialect() Dialect
+}
+
 // Column XXX
 type Column interface {
 	Name() string
-	ToSQL() string
+	Comment() string
+	ToSQL() (string, error)
+}
+
+// GeneratedColumn is a Column whose value is generated by the database, which must not be written to
+type GeneratedColumn interface {
+	Column
+	Generated() bool
+}
+
+// ColumnDefinition is a Column whose definition is given part by part,
+// which is used by the dialects changing a column clause by clause, such as ALTER COLUMN of PostgreSQL
+type ColumnDefinition interface {
+	Column
+	// TypeSQL returns the sql type with the collation, without the CHECK constraint of EnumCheck
+	TypeSQL() (string, error)
+	Null() bool
+	// DefaultSQL returns the default value in sql, and false if the column has no default
+	DefaultSQL() (string, bool)
+	// GeneratedExpr returns the expression of a generated column, and false if the column is not generated
+	GeneratedExpr() (string, bool)
+	AutoIncrement() bool
+}
+
+// EnumColumn is a Column with its enum values, which are empty for a column which is not an enum
+type EnumColumn interface {
+	Column
+	EnumValues() []string
+}
+
+// AutoIncrementColumn is a Column which reports whether it is declared with auto tag,
+// such as the column declared as INTEGER PRIMARY KEY AUTOINCREMENT by SQLite
+type AutoIncrementColumn interface {
+	Column
+	AutoIncrement() bool
 }
 
 // PrimaryKey XXX
@@ -44,6 +188,7 @@ type ForeignKeys []ForeignKey
 
 // ForeignKey XXX
 type ForeignKey interface {
+	Name() string
 	ForeignColumns() []string
 	ReferenceTableName() string
 	ReferenceColumns() []string
@@ -100,6 +245,35 @@ func (indexes Indexes) Sort() Indexes {
 	return sortIndexes
 }
 
+// Checks XXX
+type Checks []Check
+
+// Check is a CHECK constraint of a table
+type Check interface {
+	Name() string
+	Expr() string
+	ToSQL() string
+}
+
+// Sort is sort check value by alphabets
+func (checks Checks) Sort() Checks {
+	checkMap := make(map[string]Check, 0)
+	var checkStr []string
+	var sortChecks []Check
+
+	for _, check := range checks {
+		checkStr = append(checkStr, check.ToSQL())
+		checkMap[check.ToSQL()] = check
+	}
+
+	sort.Strings(checkStr)
+	for _, key := range checkStr {
+		sortChecks = append(sortChecks, checkMap[key])
+	}
+
+	return sortChecks
+}
+
 // New creates a Dialect and returns it.
 func New(driver, engine, charset string) (Dialect, error) {
 	var d Dialect
@@ -110,6 +284,10 @@ func New(driver, engine, charset string) (Dialect, error) {
 			Engine:  engine,
 			Charset: charset,
 		}
+	case "postgres":
+		d = &postgres.PostgreSQL{}
+	case "sqlite3":
+		d = &sqlite.SQLite{}
 	default:
 		return d, fmt.Errorf("No such driver: %s", driver)
 	}
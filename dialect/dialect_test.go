@@ -16,6 +16,16 @@ func TestNew(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error new dialect:%s error", "mysql")
 	}
+
+	_, err = New("postgres", "", "")
+	if err != nil {
+		t.Fatalf("error new dialect:%s error", "postgres")
+	}
+
+	_, err = New("sqlite3", "", "")
+	if err != nil {
+		t.Fatalf("error new dialect:%s error", "sqlite3")
+	}
 }
 
 func TestSort(t *testing.T) {
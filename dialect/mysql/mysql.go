@@ -2,20 +2,34 @@ package mysql
 
 import (
 	"fmt"
-	"log"
+	"strconv"
 	"strings"
+
+	"github.com/kayac/ddl-maker/dialect/typemap"
 )
 
 const (
 	defaultVarcharSize   = 191
 	defaultVarbinarySize = 767
+	maxDecimalPrecision  = 65
+	maxDecimalScale      = 30
 	autoIncrement        = "AUTO_INCREMENT"
 )
 
+// The names of table options, which override Engine and Charset
+const (
+	TableOptionEngine        = "ENGINE"
+	TableOptionCharset       = "CHARACTER SET"
+	TableOptionCollate       = "COLLATE"
+	TableOptionRowFormat     = "ROW_FORMAT"
+	TableOptionAutoIncrement = "AUTO_INCREMENT"
+)
+
 // MySQL XXX
 type MySQL struct {
 	Engine  string
 	Charset string
+	types   typemap.TypeMap
 }
 
 // Index XXX
@@ -43,6 +57,12 @@ type SpatialIndex struct {
 	name    string
 }
 
+// Check XXX
+type Check struct {
+	name string
+	expr string
+}
+
 // PrimaryKey XXX
 type PrimaryKey struct {
 	columns []string
@@ -68,6 +88,7 @@ var ForeignKeyOptionSetDefault ForeignKeyOptionType = "SET DEFAULT"
 
 // ForeignKey XXX
 type ForeignKey struct {
+	name               string
 	foreignColumns     []string
 	referenceTableName string
 	referenceColumns   []string
@@ -80,6 +101,17 @@ type ForeignKeyOption interface {
 	Apply(*ForeignKey)
 }
 
+type withForeignKeyName string
+
+func (o withForeignKeyName) Apply(f *ForeignKey) {
+	f.name = string(o)
+}
+
+// WithForeignKeyName names the foreign key constraint, which is required to drop it by a migration
+func WithForeignKeyName(name string) ForeignKeyOption {
+	return withForeignKeyName(name)
+}
+
 type withUpdateForeignKeyOption string
 
 func (o withUpdateForeignKeyOption) Apply(f *ForeignKey) {
@@ -139,81 +171,249 @@ CREATE TABLE {{ .Name }} (
     {{ range .ForeignKeys.Sort  -}}
         {{ .ToSQL }},
     {{ end -}}
+    {{ range .Checks.Sort -}}
+        {{ .ToSQL }},
+    {{ end -}}
     {{ .PrimaryKey.ToSQL }}
-) ENGINE={{ .Dialect.Engine }} DEFAULT CHARACTER SET {{ .Dialect.Charset }};
+) {{ .Dialect.TableOptionsSQL .TableOptions }}{{ if .Comment }} COMMENT={{ .Dialect.QuoteString .Comment }}{{ end }}
+{{- with .Partitioning }}
+{{ .ToSQL }}{{ end }};
+
+`
+}
+
+// AlterTableTemplate XXX
+func (mysql MySQL) AlterTableTemplate() string {
+	return `
+{{ range .DropForeignKeys -}}
+{{ if .Name -}}
+ALTER TABLE {{ $.Name }} DROP FOREIGN KEY {{ $.Dialect.Quote .Name }};
+{{ else -}}
+-- unnamed foreign key can not be dropped: ALTER TABLE {{ $.Name }} DROP {{ .ToSQL }};
+{{ end -}}
+{{ end -}}
+{{ range .DropChecks -}}
+{{ if .Name -}}
+ALTER TABLE {{ $.Name }} DROP CHECK {{ $.Dialect.Quote .Name }};
+{{ else -}}
+-- unnamed check can not be dropped: ALTER TABLE {{ $.Name }} DROP {{ .ToSQL }};
+{{ end -}}
+{{ end -}}
+{{ range .DropIndexes -}}
+ALTER TABLE {{ $.Name }} DROP INDEX {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ if .DropPrimaryKey -}}
+ALTER TABLE {{ $.Name }} DROP PRIMARY KEY;
+{{ end -}}
+{{ range .DropColumns -}}
+ALTER TABLE {{ $.Name }} DROP COLUMN {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ range .AddColumns -}}
+ALTER TABLE {{ $.Name }} ADD COLUMN {{ .ToSQL }};
+{{ end -}}
+{{ range .ModifyColumns -}}
+ALTER TABLE {{ $.Name }} MODIFY COLUMN {{ .ToSQL }};
+{{ end -}}
+{{ with .AddPrimaryKey -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end -}}
+{{ range .AddIndexes -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end -}}
+{{ range .AddChecks -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end -}}
+{{ range .AddForeignKeys -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end }}
+`
+}
+
+// DropTableTemplate XXX
+func (mysql MySQL) DropTableTemplate() string {
+	return `
+DROP TABLE IF EXISTS {{ .Name }};
 
 `
 }
 
+// TableOptionsSQL returns the options of a table, in which options override Engine and Charset
+func (mysql MySQL) TableOptionsSQL(options map[string]string) (string, error) {
+	engine, charset := mysql.Engine, mysql.Charset
+	var collate, rowFormat, autoIncrementValue string
+	for name, value := range options {
+		switch name {
+		case TableOptionEngine:
+			engine = value
+		case TableOptionCharset:
+			charset = value
+		case TableOptionCollate:
+			collate = value
+		case TableOptionRowFormat:
+			rowFormat = value
+		case TableOptionAutoIncrement:
+			if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+				return "", fmt.Errorf("%s=%s is not a number", name, value)
+			}
+			autoIncrementValue = value
+		default:
+			return "", fmt.Errorf("unknown table option %s", name)
+		}
+	}
+
+	sql := fmt.Sprintf("ENGINE=%s DEFAULT CHARACTER SET %s", engine, charset)
+	if collate != "" {
+		sql += fmt.Sprintf(" COLLATE %s", collate)
+	}
+	if rowFormat != "" {
+		sql += fmt.Sprintf(" ROW_FORMAT=%s", rowFormat)
+	}
+	if autoIncrementValue != "" {
+		sql += fmt.Sprintf(" AUTO_INCREMENT=%s", autoIncrementValue)
+	}
+
+	return sql, nil
+}
+
+// RegisterType maps the Go type t, which is a type name such as "uuid.UUID", a reflect.Type or a value of the type,
+// to the sql type returned by f. Registered types are used before the built-in types.
+func (mysql *MySQL) RegisterType(t interface{}, f typemap.TypeFunc) error {
+	if mysql.types == nil {
+		mysql.types = make(typemap.TypeMap)
+	}
+
+	return mysql.types.Register(t, f)
+}
+
 // ToSQL convert mysql sql string from typeName and size
-func (mysql MySQL) ToSQL(typeName string, size uint64) string {
-	var columns []string
+func (mysql MySQL) ToSQL(typeName string, size uint64) (string, error) {
+	if sql, ok := mysql.types.ToSQL(typeName, size); ok {
+		return sql, nil
+	}
 
 	switch typeName {
 	case "int8", "*int8":
-		return "TINYINT"
+		return "TINYINT", nil
 	case "int16", "*int16":
-		return "SMALLINT"
+		return "SMALLINT", nil
 	case "int32", "*int32", "sql.NullInt32": // from Go 1.13
-		return "INTEGER"
+		return "INTEGER", nil
 	case "int64", "*int64", "sql.NullInt64":
-		return "BIGINT"
+		return "BIGINT", nil
 	case "uint8", "*uint8":
-		return "TINYINT unsigned"
+		return "TINYINT unsigned", nil
 	case "uint16", "*uint16":
-		return "SMALLINT unsigned"
+		return "SMALLINT unsigned", nil
 	case "uint32", "*uint32":
-		return "INTEGER unsigned"
+		return "INTEGER unsigned", nil
 	case "uint64", "*uint64":
-		return "BIGINT unsigned"
+		return "BIGINT unsigned", nil
 	case "float32", "*float32":
-		return "FLOAT"
+		return "FLOAT", nil
 	case "float64", "*float64", "sql.NullFloat64":
-		return "DOUBLE"
+		return "DOUBLE", nil
 	case "string", "*string", "sql.NullString":
-		return varchar(size)
+		return varchar(size), nil
 	case "[]uint8", "sql.RawBytes":
-		return varbinary(size)
+		return varbinary(size), nil
 	case "bool", "*bool", "sql.NullBool":
-		return "TINYINT(1)"
+		return "TINYINT(1)", nil
 	case "tinytext":
-		return "TINYTEXT"
+		return "TINYTEXT", nil
 	case "text":
-		return "TEXT"
+		return "TEXT", nil
 	case "mediumtext":
-		return "MEDIUMTEXT"
+		return "MEDIUMTEXT", nil
 	case "longtext":
-		return "LONGTEXT"
+		return "LONGTEXT", nil
 	case "tinyblob":
-		return "TINYBLOB"
+		return "TINYBLOB", nil
 	case "blob":
-		return "BLOB"
+		return "BLOB", nil
 	case "mediumblob":
-		return "MEDIUMBLOB"
+		return "MEDIUMBLOB", nil
 	case "longblob":
-		return "LONGBLOB"
+		return "LONGBLOB", nil
 	case "time":
-		return "TIME"
+		return "TIME", nil
+	case "decimal", "numeric", "decimal.Decimal", "*decimal.Decimal", "decimal.NullDecimal", "big.Rat", "*big.Rat":
+		return decimal(size), nil
 	case "time.Time", "*time.Time":
-		return datetime(size)
+		return datetime(size), nil
 	case "mysql.NullTime": // https://godoc.org/github.com/go-sql-driver/mysql#NullTime
-		return datetime(size)
+		return datetime(size), nil
 	case "sql.NullTime": // from Go 1.13
-		return datetime(size)
+		return datetime(size), nil
 	case "date":
-		return "DATE"
+		return "DATE", nil
 	case "json.RawMessage", "*json.RawMessage":
-		return "JSON"
+		return "JSON", nil
 	case "geometry":
-		return "GEOMETRY"
+		return "GEOMETRY", nil
 	default:
-		log.Fatalf("%s is not match.", typeName)
+		return "", fmt.Errorf("%s is not match", typeName)
+	}
+}
+
+// CollateSQL returns the column type with CHARACTER SET and COLLATE
+func (mysql MySQL) CollateSQL(typeSQL, charset, collate string) (string, error) {
+	if charset != "" {
+		typeSQL += fmt.Sprintf(" CHARACTER SET %s", charset)
+	}
+	if collate != "" {
+		typeSQL += fmt.Sprintf(" COLLATE %s", collate)
+	}
+
+	return typeSQL, nil
+}
+
+// DecimalToSQL returns DECIMAL with precision and scale for a decimal type
+func (mysql MySQL) DecimalToSQL(typeName string, precision, scale uint64) (string, error) {
+	sql, err := mysql.ToSQL(typeName, 0)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(sql, "DECIMAL") {
+		return "", fmt.Errorf("%s does not support precision", typeName)
+	}
+	if precision == 0 || precision > maxDecimalPrecision || scale > maxDecimalScale {
+		return "", fmt.Errorf("DECIMAL(%d,%d) is out of range", precision, scale)
 	}
 
-	if size != 0 {
-		columns = append(columns, fmt.Sprintf("(%d)", size))
+	return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale), nil
+}
+
+// EnumToSQL returns ENUM, or SET when typeName is "set", with the values
+func (mysql MySQL) EnumToSQL(column, typeName string, size uint64, values []string) (string, error) {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteString(v))
+	}
+	if typeName == "set" {
+		return fmt.Sprintf("SET(%s)", strings.Join(quoted, ",")), nil
+	}
+
+	return fmt.Sprintf("ENUM(%s)", strings.Join(quoted, ",")), nil
+}
+
+// AllowForwardReference XXX
+// foreign_key_checks is disabled by HeaderTemplate.
+func (mysql MySQL) AllowForwardReference() bool {
+	return true
+}
+
+// SupportPartitioning XXX
+func (mysql MySQL) SupportPartitioning() bool {
+	return true
+}
+
+// GeneratedSQL returns the clause of a generated column, which is VIRTUAL unless stored
+func (mysql MySQL) GeneratedSQL(expr string, stored bool) string {
+	if stored {
+		return fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", expr)
 	}
-	return strings.Join(columns, "")
+
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) VIRTUAL", expr)
 }
 
 // Quote XXX
@@ -226,6 +426,21 @@ func (mysql MySQL) AutoIncrement() string {
 	return autoIncrement
 }
 
+// QuoteString returns s as a string literal
+func (mysql MySQL) QuoteString(s string) string {
+	return quoteString(s)
+}
+
+// OnUpdateSQL returns the ON UPDATE clause of a column
+func (mysql MySQL) OnUpdateSQL(expr string) string {
+	return fmt.Sprintf("ON UPDATE %s", expr)
+}
+
+// CommentSQL returns the COMMENT clause of a column
+func (mysql MySQL) CommentSQL(comment string) string {
+	return fmt.Sprintf("COMMENT %s", quoteString(comment))
+}
+
 // Name XXX
 func (i Index) Name() string {
 	return i.name
@@ -332,6 +547,11 @@ func (pk PrimaryKey) ToSQL() string {
 	return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(columnsStr, ", "))
 }
 
+// Name XXX
+func (fk ForeignKey) Name() string {
+	return fk.name
+}
+
 // ForeignColumns XXX
 func (fk ForeignKey) ForeignColumns() []string {
 	return fk.foreignColumns
@@ -376,10 +596,32 @@ func (fk ForeignKey) ToSQL() string {
 	if fk.updateOption != "" {
 		sql = sql + fmt.Sprintf(" ON UPDATE %s", fk.updateOption)
 	}
+	if fk.name != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(fk.name)) + sql
+	}
 	return sql
 
 }
 
+// Name XXX
+func (c Check) Name() string {
+	return c.name
+}
+
+// Expr XXX
+func (c Check) Expr() string {
+	return c.expr
+}
+
+// ToSQL XXX
+func (c Check) ToSQL() string {
+	sql := fmt.Sprintf("CHECK (%s)", c.expr)
+	if c.name != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(c.name)) + sql
+	}
+	return sql
+}
+
 // AddIndex XXX
 func AddIndex(idxName string, columns ...string) Index {
 	return Index{
@@ -419,6 +661,14 @@ func AddPrimaryKey(columns ...string) PrimaryKey {
 	}
 }
 
+// AddCheck adds CHECK (expr) constraint, which is unnamed if name is empty
+func AddCheck(name, expr string) Check {
+	return Check{
+		name: name,
+		expr: expr,
+	}
+}
+
 // AddForeignKey XXX
 func AddForeignKey(foreignColumns, referenceColumns []string, referenceTableName string, option ...ForeignKeyOption) ForeignKey {
 	foreignKey := ForeignKey{
@@ -452,6 +702,14 @@ func varbinary(size uint64) string {
 	return fmt.Sprintf("VARBINARY(%d)", size)
 }
 
+func decimal(size uint64) string {
+	if size == 0 {
+		return fmt.Sprintf("DECIMAL(%d,%d)", maxDecimalPrecision, maxDecimalScale)
+	}
+
+	return fmt.Sprintf("DECIMAL(%d,0)", size)
+}
+
 func datetime(size uint64) string {
 	if size == 0 {
 		return "DATETIME"
@@ -463,3 +721,8 @@ func datetime(size uint64) string {
 func quote(s string) string {
 	return fmt.Sprintf("`%s`", s)
 }
+
+func quoteString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+}
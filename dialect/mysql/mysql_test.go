@@ -66,9 +66,79 @@ func TestToSQL(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
-		if m.ToSQL(tc.typeName, tc.size) != tc.output {
-			t.Fatalf("error %s to sql %s. but result %s", tc.typeName, tc.output, m.ToSQL(tc.typeName, tc.size))
+		sql, err := m.ToSQL(tc.typeName, tc.size)
+		if err != nil {
+			t.Fatalf("error %s to sql. %s", tc.typeName, err)
 		}
+		if sql != tc.output {
+			t.Fatalf("error %s to sql %s. but result %s", tc.typeName, tc.output, sql)
+		}
+	}
+
+	if _, err := m.ToSQL("mypkg.Status", 0); err == nil {
+		t.Fatal("error unknown type to sql")
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	m := &MySQL{}
+
+	err := m.RegisterType("uuid.UUID", func(size uint64) string {
+		return "BINARY(16)"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// registered types are used before the built-in types
+	err = m.RegisterType("string", func(size uint64) string {
+		return "TEXT"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		typeName string
+		output   string
+	}{
+		{"uuid.UUID", "BINARY(16)"},
+		{"*uuid.UUID", "BINARY(16)"},
+		{"string", "TEXT"},
+		{"int64", "BIGINT"},
+	}
+	for _, tc := range testcases {
+		sql, err := m.ToSQL(tc.typeName, 0)
+		if err != nil || sql != tc.output {
+			t.Fatalf("error %s to sql %s. but result %s, %v", tc.typeName, tc.output, sql, err)
+		}
+	}
+}
+
+func TestDecimalToSQL(t *testing.T) {
+	m := MySQL{}
+
+	testcases := []struct {
+		typeName  string
+		precision uint64
+		scale     uint64
+		output    string
+	}{
+		{"decimal.Decimal", 12, 2, "DECIMAL(12,2)"},
+		{"big.Rat", 65, 30, "DECIMAL(65,30)"},
+		{"decimal", 10, 0, "DECIMAL(10,0)"},
+	}
+	for _, tc := range testcases {
+		sql, err := m.DecimalToSQL(tc.typeName, tc.precision, tc.scale)
+		if err != nil || sql != tc.output {
+			t.Fatalf("error %s to sql %s. but result %s, %v", tc.typeName, tc.output, sql, err)
+		}
+	}
+
+	if _, err := m.DecimalToSQL("float64", 12, 2); err == nil {
+		t.Fatal("error precision of float64 to sql")
+	}
+	if _, err := m.DecimalToSQL("decimal", 66, 2); err == nil {
+		t.Fatal("error precision out of range to sql")
 	}
 }
 
@@ -185,4 +255,48 @@ func TestAddForeignKey(t *testing.T) {
 	if fk.ToSQL() != "FOREIGN KEY (`product_category`, `product_id`) REFERENCES `product` (`category`, `id`) ON UPDATE CASCADE" {
 		t.Fatal("[error] parse foreign key", fk.ToSQL())
 	}
+
+	fk = AddForeignKey([]string{"player_id"}, []string{"id"}, "player", WithForeignKeyName("player_fk"))
+	if fk.ToSQL() != "CONSTRAINT `player_fk` FOREIGN KEY (`player_id`) REFERENCES `player` (`id`)" {
+		t.Fatal("[error] parse foreign key", fk.ToSQL())
+	}
+}
+
+func TestAddCheck(t *testing.T) {
+	check := AddCheck("", "price >= 0")
+	if check.ToSQL() != "CHECK (price >= 0)" {
+		t.Fatal("[error] parse check", check.ToSQL())
+	}
+
+	check = AddCheck("price_positive", "price >= 0")
+	if check.ToSQL() != "CONSTRAINT `price_positive` CHECK (price >= 0)" {
+		t.Fatal("[error] parse check", check.ToSQL())
+	}
+}
+
+func TestTableOptionsSQL(t *testing.T) {
+	m := MySQL{Engine: "InnoDB", Charset: "utf8mb4"}
+
+	testcases := []struct {
+		options map[string]string
+		sql     string
+	}{
+		{nil, "ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4"},
+		{map[string]string{TableOptionEngine: "MyISAM", TableOptionCharset: "ascii"}, "ENGINE=MyISAM DEFAULT CHARACTER SET ascii"},
+		{
+			map[string]string{TableOptionCollate: "utf8mb4_bin", TableOptionRowFormat: "COMPRESSED", TableOptionAutoIncrement: "1000000"},
+			"ENGINE=InnoDB DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_bin ROW_FORMAT=COMPRESSED AUTO_INCREMENT=1000000",
+		},
+	}
+	for _, tc := range testcases {
+		if sql, err := m.TableOptionsSQL(tc.options); sql != tc.sql || err != nil {
+			t.Fatalf("error table options %v. result: %s, %v", tc.options, sql, err)
+		}
+	}
+
+	for _, options := range []map[string]string{{"UNKNOWN": "1"}, {TableOptionAutoIncrement: "a"}} {
+		if _, err := m.TableOptionsSQL(options); err == nil {
+			t.Fatalf("error table options %v is accepted", options)
+		}
+	}
 }
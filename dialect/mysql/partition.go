@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	identifierRe = regexp.MustCompile("`?([A-Za-z_][A-Za-z0-9_]*)`?(\\s*\\()?")
+	// string literals in a partitioning expression, whose content is not identifiers
+	stringLiteralRe = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"`)
+	// operators and keywords which are not columns in a partitioning expression
+	exprKeywords = map[string]bool{
+		"DIV": true, "MOD": true, "AND": true, "OR": true, "XOR": true, "NOT": true, "IS": true, "NULL": true, "IN": true,
+		"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true, "BETWEEN": true, "LIKE": true,
+		"TRUE": true, "FALSE": true,
+	}
+)
+
+// Partitioning is the PARTITION BY clause of a table, which is created by PartitionByRange and the other PartitionBy functions
+type Partitioning struct {
+	method     string
+	expr       string
+	columns    []string
+	num        uint64
+	partitions []Partition
+}
+
+// Partition is a partition of RANGE or LIST partitioning
+type Partition struct {
+	name   string
+	values []string
+}
+
+// AddPartition adds a partition with the values, which are written as they are.
+// The values are VALUES LESS THAN of RANGE partitioning, where MAXVALUE is allowed, or VALUES IN of LIST partitioning.
+func AddPartition(name string, values ...string) Partition {
+	return Partition{
+		name:   name,
+		values: values,
+	}
+}
+
+// PartitionByRange partitions a table by RANGE (expr), such as RANGE (TO_DAYS(created_at))
+func PartitionByRange(expr string, partitions ...Partition) Partitioning {
+	return Partitioning{
+		method:     "RANGE",
+		expr:       expr,
+		columns:    exprColumns(expr),
+		partitions: partitions,
+	}
+}
+
+// PartitionByRangeColumns partitions a table by RANGE COLUMNS, which accepts DATE, DATETIME and string columns
+func PartitionByRangeColumns(columns []string, partitions ...Partition) Partitioning {
+	return Partitioning{
+		method:     "RANGE COLUMNS",
+		columns:    columns,
+		partitions: partitions,
+	}
+}
+
+// PartitionByList partitions a table by LIST (expr)
+func PartitionByList(expr string, partitions ...Partition) Partitioning {
+	return Partitioning{
+		method:     "LIST",
+		expr:       expr,
+		columns:    exprColumns(expr),
+		partitions: partitions,
+	}
+}
+
+// PartitionByListColumns partitions a table by LIST COLUMNS
+func PartitionByListColumns(columns []string, partitions ...Partition) Partitioning {
+	return Partitioning{
+		method:     "LIST COLUMNS",
+		columns:    columns,
+		partitions: partitions,
+	}
+}
+
+// PartitionByHash partitions a table into num partitions by HASH (expr)
+func PartitionByHash(expr string, num uint64) Partitioning {
+	return Partitioning{
+		method:  "HASH",
+		expr:    expr,
+		columns: exprColumns(expr),
+		num:     num,
+	}
+}
+
+// PartitionByKey partitions a table into num partitions by KEY (columns), which is the primary key if columns is empty
+func PartitionByKey(columns []string, num uint64) Partitioning {
+	return Partitioning{
+		method:  "KEY",
+		columns: columns,
+		num:     num,
+	}
+}
+
+// Columns returns the columns used by the partitioning, which must be a part of every unique key
+func (p Partitioning) Columns() []string {
+	return p.columns
+}
+
+// ToSQL returns the PARTITION BY clause with the partitions, which is written after the table options
+func (p Partitioning) ToSQL() string {
+	var sql string
+	switch p.method {
+	case "RANGE COLUMNS", "LIST COLUMNS":
+		sql = fmt.Sprintf("PARTITION BY %s(%s)", p.method, quoteColumns(p.columns))
+	case "KEY":
+		sql = fmt.Sprintf("PARTITION BY KEY (%s)", quoteColumns(p.columns))
+	default:
+		sql = fmt.Sprintf("PARTITION BY %s (%s)", p.method, p.expr)
+	}
+	if p.num > 0 {
+		sql += fmt.Sprintf(" PARTITIONS %d", p.num)
+	}
+	if len(p.partitions) == 0 {
+		return sql
+	}
+
+	var partitions []string
+	for _, partition := range p.partitions {
+		partitions = append(partitions, "    "+partition.toSQL(p.method))
+	}
+
+	return fmt.Sprintf("%s (\n%s\n)", sql, strings.Join(partitions, ",\n"))
+}
+
+// toSQL returns the partition of the partitioning method, in which MAXVALUE is not enclosed in parentheses only by RANGE (expr)
+func (p Partition) toSQL(method string) string {
+	values := strings.Join(p.values, ", ")
+	if !strings.HasPrefix(method, "RANGE") {
+		return fmt.Sprintf("PARTITION %s VALUES IN (%s)", quote(p.name), values)
+	}
+	if method == "RANGE" && strings.EqualFold(values, "MAXVALUE") {
+		return fmt.Sprintf("PARTITION %s VALUES LESS THAN MAXVALUE", quote(p.name))
+	}
+
+	return fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", quote(p.name), values)
+}
+
+// exprColumns returns the identifiers in expr except function names, operators and string literals, without duplicates
+func exprColumns(expr string) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, m := range identifierRe.FindAllStringSubmatch(stringLiteralRe.ReplaceAllString(expr, " "), -1) {
+		quoted := strings.HasPrefix(m[0], "`")
+		if m[2] != "" || (!quoted && exprKeywords[strings.ToUpper(m[1])]) || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		columns = append(columns, m[1])
+	}
+
+	return columns
+}
+
+func quoteColumns(columns []string) string {
+	var columnsStr []string
+	for _, c := range columns {
+		columnsStr = append(columnsStr, quote(c))
+	}
+
+	return strings.Join(columnsStr, ", ")
+}
@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartitioning(t *testing.T) {
+	testcases := []struct {
+		partitioning Partitioning
+		sql          string
+		columns      []string
+	}{
+		{
+			PartitionByRange("TO_DAYS(created_at)", AddPartition("p2023", "TO_DAYS('2024-01-01')"), AddPartition("pmax", "MAXVALUE")),
+			"PARTITION BY RANGE (TO_DAYS(created_at)) (\n" +
+				"    PARTITION `p2023` VALUES LESS THAN (TO_DAYS('2024-01-01')),\n" +
+				"    PARTITION `pmax` VALUES LESS THAN MAXVALUE\n" +
+				")",
+			[]string{"created_at"},
+		},
+		{
+			PartitionByRangeColumns([]string{"created_at"}, AddPartition("p2023", "'2024-01-01'"), AddPartition("pmax", "MAXVALUE")),
+			"PARTITION BY RANGE COLUMNS(`created_at`) (\n" +
+				"    PARTITION `p2023` VALUES LESS THAN ('2024-01-01'),\n" +
+				"    PARTITION `pmax` VALUES LESS THAN (MAXVALUE)\n" +
+				")",
+			[]string{"created_at"},
+		},
+		{
+			PartitionByList("region_id DIV 10", AddPartition("east", "1", "2"), AddPartition("west", "3")),
+			"PARTITION BY LIST (region_id DIV 10) (\n" +
+				"    PARTITION `east` VALUES IN (1, 2),\n" +
+				"    PARTITION `west` VALUES IN (3)\n" +
+				")",
+			[]string{"region_id"},
+		},
+		{
+			PartitionByListColumns([]string{"country"}, AddPartition("asia", "'JP'", "'KR'")),
+			"PARTITION BY LIST COLUMNS(`country`) (\n" +
+				"    PARTITION `asia` VALUES IN ('JP', 'KR')\n" +
+				")",
+			[]string{"country"},
+		},
+		{
+			PartitionByHash("YEAR(`created_at`)", 4),
+			"PARTITION BY HASH (YEAR(`created_at`)) PARTITIONS 4",
+			[]string{"created_at"},
+		},
+		{
+			PartitionByHash("CASE WHEN `status` = 'end' THEN region_id ELSE region_id + status END", 4),
+			"PARTITION BY HASH (CASE WHEN `status` = 'end' THEN region_id ELSE region_id + status END) PARTITIONS 4",
+			[]string{"status", "region_id"},
+		},
+		{
+			PartitionByKey([]string{"id"}, 8),
+			"PARTITION BY KEY (`id`) PARTITIONS 8",
+			[]string{"id"},
+		},
+		{
+			PartitionByKey(nil, 8),
+			"PARTITION BY KEY () PARTITIONS 8",
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		if tc.partitioning.ToSQL() != tc.sql {
+			t.Fatalf("[error] partitioning. result: %s expected: %s", tc.partitioning.ToSQL(), tc.sql)
+		}
+		if !reflect.DeepEqual(tc.partitioning.Columns(), tc.columns) {
+			t.Fatalf("[error] partitioning columns. result: %v expected: %v", tc.partitioning.Columns(), tc.columns)
+		}
+	}
+}
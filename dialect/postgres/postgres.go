@@ -0,0 +1,720 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kayac/ddl-maker/dialect/typemap"
+)
+
+const (
+	autoIncrement       = "GENERATED BY DEFAULT AS IDENTITY"
+	maxNumericPrecision = 1000
+)
+
+// TableOptionTablespace is the name of the table option of TABLESPACE, and other options are storage parameters such as fillfactor
+const TableOptionTablespace = "TABLESPACE"
+
+// storageParameters are the storage parameters of a table.
+// The parameters which start with autovacuum_ or vacuum_, except autovacuum_analyze_, can also be set to the TOAST table by toast. prefix.
+var storageParameters = map[string]bool{
+	"fillfactor":                            true,
+	"toast_tuple_target":                    true,
+	"parallel_workers":                      true,
+	"autovacuum_enabled":                    true,
+	"vacuum_index_cleanup":                  true,
+	"vacuum_truncate":                       true,
+	"autovacuum_vacuum_threshold":           true,
+	"autovacuum_vacuum_max_threshold":       true,
+	"autovacuum_vacuum_scale_factor":        true,
+	"autovacuum_vacuum_insert_threshold":    true,
+	"autovacuum_vacuum_insert_scale_factor": true,
+	"autovacuum_analyze_threshold":          true,
+	"autovacuum_analyze_scale_factor":       true,
+	"autovacuum_vacuum_cost_delay":          true,
+	"autovacuum_vacuum_cost_limit":          true,
+	"autovacuum_freeze_min_age":             true,
+	"autovacuum_freeze_max_age":             true,
+	"autovacuum_freeze_table_age":           true,
+	"autovacuum_multixact_freeze_min_age":   true,
+	"autovacuum_multixact_freeze_max_age":   true,
+	"autovacuum_multixact_freeze_table_age": true,
+	"log_autovacuum_min_duration":           true,
+	"user_catalog_table":                    true,
+}
+
+// PostgreSQL XXX
+type PostgreSQL struct {
+	types typemap.TypeMap
+}
+
+// Index XXX
+type Index struct {
+	columns []string
+	name    string
+}
+
+// UniqueIndex XXX
+type UniqueIndex struct {
+	columns []string
+	name    string
+}
+
+// Check XXX
+type Check struct {
+	name string
+	expr string
+}
+
+// AlterColumn is the column whose definition is given part by part, which is dialect.ColumnDefinition
+type AlterColumn interface {
+	Name() string
+	TypeSQL() (string, error)
+	Null() bool
+	DefaultSQL() (string, bool)
+	GeneratedExpr() (string, bool)
+	AutoIncrement() bool
+}
+
+// EnumColumn is the column with its enum values of any dialect
+type EnumColumn interface {
+	Name() string
+	EnumValues() []string
+}
+
+// PrimaryKey XXX
+type PrimaryKey struct {
+	columns []string
+}
+
+// ForeignKeyOptionType XXX
+type ForeignKeyOptionType string
+
+// ForeignKeyOptionCascade CASCADE
+var ForeignKeyOptionCascade ForeignKeyOptionType = "CASCADE"
+
+// ForeignKeyOptionSetNull SET NULL
+var ForeignKeyOptionSetNull ForeignKeyOptionType = "SET NULL"
+
+// ForeignKeyOptionRestrict RESTRICT
+var ForeignKeyOptionRestrict ForeignKeyOptionType = "RESTRICT"
+
+// ForeignKeyOptionNoAction NO ACTION
+var ForeignKeyOptionNoAction ForeignKeyOptionType = "NO ACTION"
+
+// ForeignKeyOptionSetDefault SET DEFAULT
+var ForeignKeyOptionSetDefault ForeignKeyOptionType = "SET DEFAULT"
+
+// ForeignKey XXX
+type ForeignKey struct {
+	name               string
+	foreignColumns     []string
+	referenceTableName string
+	referenceColumns   []string
+	updateOption       string
+	deleteOption       string
+}
+
+// ForeignKeyOption XXX
+type ForeignKeyOption interface {
+	Apply(*ForeignKey)
+}
+
+type withForeignKeyName string
+
+func (o withForeignKeyName) Apply(f *ForeignKey) {
+	f.name = string(o)
+}
+
+// WithForeignKeyName names the foreign key constraint, which is required to drop it by a migration
+func WithForeignKeyName(name string) ForeignKeyOption {
+	return withForeignKeyName(name)
+}
+
+type withUpdateForeignKeyOption string
+
+func (o withUpdateForeignKeyOption) Apply(f *ForeignKey) {
+	f.updateOption = string(o)
+}
+
+// WithUpdateForeignKeyOption XXX
+func WithUpdateForeignKeyOption(option ForeignKeyOptionType) ForeignKeyOption {
+	switch option {
+	// NO ACTION is the default referential action.
+	case ForeignKeyOptionNoAction:
+		return withUpdateForeignKeyOption("")
+	}
+	return withUpdateForeignKeyOption(option)
+}
+
+type withDeleteForeignKeyOption string
+
+func (o withDeleteForeignKeyOption) Apply(f *ForeignKey) {
+	f.deleteOption = string(o)
+}
+
+// WithDeleteForeignKeyOption XXX
+func WithDeleteForeignKeyOption(option ForeignKeyOptionType) ForeignKeyOption {
+	switch option {
+	// NO ACTION is the default referential action.
+	case ForeignKeyOptionNoAction:
+		return withDeleteForeignKeyOption("")
+	}
+	return withDeleteForeignKeyOption(option)
+}
+
+// HeaderTemplate XXX
+func (pg PostgreSQL) HeaderTemplate() string {
+	return `BEGIN;
+`
+}
+
+// FooterTemplate XXX
+func (pg PostgreSQL) FooterTemplate() string {
+	return `COMMIT;
+`
+}
+
+// TableTemplate XXX
+// PostgreSQL has no inline INDEX definition, so indexes are created after the table.
+func (pg PostgreSQL) TableTemplate() string {
+	return `
+DROP TABLE IF EXISTS {{ .Name }} CASCADE;
+
+CREATE TABLE {{ .Name }} (
+    {{ range .Columns -}}
+        {{ .ToSQL }},
+    {{ end -}}
+    {{ range .ForeignKeys.Sort  -}}
+        {{ .ToSQL }},
+    {{ end -}}
+    {{ range .Checks.Sort -}}
+        {{ .ToSQL }},
+    {{ end -}}
+    {{ .PrimaryKey.ToSQL }}
+){{ .Dialect.TableOptionsSQL .TableOptions }};
+{{ range .Indexes.Sort -}}
+{{ .CreateSQL $.Name }};
+{{ end -}}
+{{ if .Comment -}}
+COMMENT ON TABLE {{ .Name }} IS {{ .Dialect.QuoteString .Comment }};
+{{ end -}}
+{{ range .Columns -}}
+{{ if .Comment -}}
+COMMENT ON COLUMN {{ $.Name }}.{{ $.Dialect.Quote .Name }} IS {{ $.Dialect.QuoteString .Comment }};
+{{ end -}}
+{{ end }}
+`
+}
+
+// AlterTableTemplate XXX
+func (pg PostgreSQL) AlterTableTemplate() string {
+	return `
+{{ range .DropForeignKeys -}}
+{{ if .Name -}}
+ALTER TABLE {{ $.Name }} DROP CONSTRAINT {{ $.Dialect.Quote .Name }};
+{{ else -}}
+-- unnamed foreign key can not be dropped: ALTER TABLE {{ $.Name }} DROP {{ .ToSQL }};
+{{ end -}}
+{{ end -}}
+{{ range .DropChecks -}}
+{{ if .Name -}}
+ALTER TABLE {{ $.Name }} DROP CONSTRAINT {{ $.Dialect.Quote .Name }};
+{{ else -}}
+-- unnamed check can not be dropped: ALTER TABLE {{ $.Name }} DROP {{ .ToSQL }};
+{{ end -}}
+{{ end -}}
+{{ range .DropIndexes -}}
+DROP INDEX {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ if .DropPrimaryKey -}}
+ALTER TABLE {{ $.Name }} DROP CONSTRAINT {{ $.Dialect.PrimaryKeyName $.Name }};
+{{ end -}}
+{{ range .DropColumns -}}
+ALTER TABLE {{ $.Name }} DROP COLUMN {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ range .AddColumns -}}
+ALTER TABLE {{ $.Name }} ADD COLUMN {{ .ToSQL }};
+{{ if .Comment -}}
+COMMENT ON COLUMN {{ $.Name }}.{{ $.Dialect.Quote .Name }} IS {{ $.Dialect.QuoteString .Comment }};
+{{ end -}}
+{{ end -}}
+{{ range .ModifyColumns -}}
+ALTER TABLE {{ $.Name }} {{ $.Dialect.AlterColumnSQL . }};
+COMMENT ON COLUMN {{ $.Name }}.{{ $.Dialect.Quote .Name }} IS {{ if .Comment }}{{ $.Dialect.QuoteString .Comment }}{{ else }}NULL{{ end }};
+{{ end -}}
+{{ range .ModifyEnumColumns -}}
+ALTER TABLE {{ $.Name }} {{ $.Dialect.AlterEnumSQL $.Name . }};
+{{ end -}}
+{{ with .AddPrimaryKey -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end -}}
+{{ range .AddIndexes -}}
+{{ .CreateSQL $.Name }};
+{{ end -}}
+{{ range .AddChecks -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end -}}
+{{ range .AddForeignKeys -}}
+ALTER TABLE {{ $.Name }} ADD {{ .ToSQL }};
+{{ end }}
+`
+}
+
+// DropTableTemplate XXX
+func (pg PostgreSQL) DropTableTemplate() string {
+	return `
+DROP TABLE IF EXISTS {{ .Name }} CASCADE;
+
+`
+}
+
+// TableOptionsSQL returns the storage parameters and the tablespace of a table, which starts with a space if any.
+// It returns an error for an option which is not a storage parameter, such as an option of MySQL.
+func (pg PostgreSQL) TableOptionsSQL(options map[string]string) (string, error) {
+	var params []string
+	for name, value := range options {
+		if name == TableOptionTablespace {
+			continue
+		}
+		if !isStorageParameter(name) {
+			return "", fmt.Errorf("unknown table option %s", name)
+		}
+		params = append(params, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(params)
+
+	var sql string
+	if len(params) > 0 {
+		sql += fmt.Sprintf(" WITH (%s)", strings.Join(params, ", "))
+	}
+	if tablespace := options[TableOptionTablespace]; tablespace != "" {
+		sql += fmt.Sprintf(" TABLESPACE %s", quote(tablespace))
+	}
+
+	return sql, nil
+}
+
+func isStorageParameter(name string) bool {
+	if toast := strings.TrimPrefix(name, "toast."); toast != name {
+		return (strings.HasPrefix(toast, "autovacuum_") || strings.HasPrefix(toast, "vacuum_")) &&
+			!strings.HasPrefix(toast, "autovacuum_analyze_") && storageParameters[toast]
+	}
+
+	return storageParameters[name]
+}
+
+// RegisterType maps the Go type t, which is a type name such as "uuid.UUID", a reflect.Type or a value of the type,
+// to the sql type returned by f. Registered types are used before the built-in types.
+func (pg *PostgreSQL) RegisterType(t interface{}, f typemap.TypeFunc) error {
+	if pg.types == nil {
+		pg.types = make(typemap.TypeMap)
+	}
+
+	return pg.types.Register(t, f)
+}
+
+// ToSQL convert postgres sql string from typeName and size
+func (pg PostgreSQL) ToSQL(typeName string, size uint64) (string, error) {
+	if sql, ok := pg.types.ToSQL(typeName, size); ok {
+		return sql, nil
+	}
+
+	switch typeName {
+	case "int8", "*int8", "int16", "*int16", "uint8", "*uint8":
+		return "SMALLINT", nil
+	case "int32", "*int32", "sql.NullInt32", "uint16", "*uint16":
+		return "INTEGER", nil
+	// uint64 over 2^63-1 does not fit, but an identity column needs an integer type
+	case "int64", "*int64", "sql.NullInt64", "uint32", "*uint32", "uint64", "*uint64":
+		return "BIGINT", nil
+	case "float32", "*float32":
+		return "REAL", nil
+	case "float64", "*float64", "sql.NullFloat64":
+		return "DOUBLE PRECISION", nil
+	case "string", "*string", "sql.NullString":
+		return varchar(size), nil
+	case "[]uint8", "sql.RawBytes":
+		return "BYTEA", nil
+	case "bool", "*bool", "sql.NullBool":
+		return "BOOLEAN", nil
+	case "tinytext", "text", "mediumtext", "longtext":
+		return "TEXT", nil
+	case "tinyblob", "blob", "mediumblob", "longblob":
+		return "BYTEA", nil
+	case "time":
+		return "TIME", nil
+	case "decimal", "numeric", "decimal.Decimal", "*decimal.Decimal", "decimal.NullDecimal", "big.Rat", "*big.Rat":
+		return numeric(size), nil
+	case "time.Time", "*time.Time", "pq.NullTime", "sql.NullTime":
+		return timestamptz(size), nil
+	case "date":
+		return "DATE", nil
+	case "json.RawMessage", "*json.RawMessage":
+		return "JSONB", nil
+	case "geometry":
+		return "GEOMETRY", nil
+	default:
+		return "", fmt.Errorf("%s is not match", typeName)
+	}
+}
+
+// CollateSQL returns the column type with COLLATE, which is put before the CHECK constraint of enum values.
+// The character set of a column is not supported, which is the encoding of the database.
+func (pg PostgreSQL) CollateSQL(typeSQL, charset, collate string) (string, error) {
+	if charset != "" {
+		return "", fmt.Errorf("character set of a column is not supported")
+	}
+	if collate == "" {
+		return typeSQL, nil
+	}
+
+	check := ""
+	if i := strings.Index(typeSQL, " CHECK ("); i >= 0 {
+		typeSQL, check = typeSQL[:i], typeSQL[i:]
+	}
+
+	return fmt.Sprintf("%s COLLATE %s%s", typeSQL, quote(collate), check), nil
+}
+
+// DecimalToSQL returns NUMERIC with precision and scale for a decimal type
+func (pg PostgreSQL) DecimalToSQL(typeName string, precision, scale uint64) (string, error) {
+	sql, err := pg.ToSQL(typeName, 0)
+	if err != nil {
+		return "", err
+	}
+	if sql != "NUMERIC" {
+		return "", fmt.Errorf("%s does not support precision", typeName)
+	}
+	if precision == 0 || precision > maxNumericPrecision {
+		return "", fmt.Errorf("NUMERIC(%d,%d) is out of range", precision, scale)
+	}
+
+	return fmt.Sprintf("NUMERIC(%d,%d)", precision, scale), nil
+}
+
+// EnumToSQL returns the type of typeName with a CHECK constraint of the values.
+// "enum" is stored as a string, and "set" is not supported.
+func (pg PostgreSQL) EnumToSQL(column, typeName string, size uint64, values []string) (string, error) {
+	sql, err := pg.EnumTypeSQL(typeName, size)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s", sql, enumCheckSQL(column, values)), nil
+}
+
+// EnumTypeSQL returns the type of typeName of an enum column without the CHECK constraint
+func (pg PostgreSQL) EnumTypeSQL(typeName string, size uint64) (string, error) {
+	switch typeName {
+	case "set":
+		return "", fmt.Errorf("set is not supported")
+	case "enum":
+		typeName = "string"
+	}
+
+	return pg.ToSQL(typeName, size)
+}
+
+// AlterEnumSQL returns the clauses which replace the CHECK constraint of the enum values of column in the quoted table.
+// The constraint is named <table>_<column>_check, which is the name of the CHECK constraint of a column by PostgreSQL,
+// and is only dropped if the column is no longer an enum.
+func (pg PostgreSQL) AlterEnumSQL(table string, column EnumColumn) string {
+	name := quote(fmt.Sprintf("%s_%s_check", strings.Trim(table, `"`), column.Name()))
+	sql := fmt.Sprintf("DROP CONSTRAINT IF EXISTS %s", name)
+	if values := column.EnumValues(); len(values) > 0 {
+		sql += fmt.Sprintf(", ADD CONSTRAINT %s %s", name, enumCheckSQL(column.Name(), values))
+	}
+
+	return sql
+}
+
+func enumCheckSQL(column string, values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteString(v))
+	}
+
+	return fmt.Sprintf("CHECK (%s IN (%s))", quote(column), strings.Join(quoted, ", "))
+}
+
+// GeneratedSQL returns the clause of a generated column, which is VIRTUAL unless stored
+func (pg PostgreSQL) GeneratedSQL(expr string, stored bool) string {
+	if stored {
+		return fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", expr)
+	}
+
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) VIRTUAL", expr)
+}
+
+// Quote XXX
+func (pg PostgreSQL) Quote(s string) string {
+	return quote(s)
+}
+
+// AutoIncrement XXX
+func (pg PostgreSQL) AutoIncrement() string {
+	return autoIncrement
+}
+
+// PrimaryKeyName returns the quoted name of the primary key of the quoted table, which is named <table>_pkey by PostgreSQL
+func (pg PostgreSQL) PrimaryKeyName(table string) string {
+	return quote(strings.Trim(table, `"`) + "_pkey")
+}
+
+// QuoteString returns s as a string literal
+func (pg PostgreSQL) QuoteString(s string) string {
+	return quoteString(s)
+}
+
+// SchemaIndexName XXX
+// An index of PostgreSQL is a relation of the schema, so its name must be unique among all tables.
+func (pg PostgreSQL) SchemaIndexName() bool {
+	return true
+}
+
+// AlterColumnSQL return ALTER COLUMN clauses which change a column to the definition of column, which is an AlterColumn.
+// PostgreSQL has no MODIFY COLUMN, so the type, nullability and default are changed one by one.
+// The CHECK constraint of enum values is changed by AlterEnumSQL.
+func (pg PostgreSQL) AlterColumnSQL(column interface{}) (string, error) {
+	c, ok := column.(AlterColumn)
+	if !ok {
+		return "", fmt.Errorf("column %T can not be altered", column)
+	}
+	name := quote(c.Name())
+	typeSQL, err := c.TypeSQL()
+	if err != nil {
+		return "", err
+	}
+
+	clauses := []string{fmt.Sprintf("ALTER COLUMN %s TYPE %s", name, typeSQL)}
+	if c.Null() {
+		clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", name))
+	} else {
+		clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", name))
+	}
+	if expr, ok := c.GeneratedExpr(); ok {
+		// generated column has no default
+		clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET EXPRESSION AS (%s)", name, expr))
+		return strings.Join(clauses, ", "), nil
+	}
+	if c.AutoIncrement() {
+		// identity column has no default
+		return strings.Join(clauses, ", "), nil
+	}
+	if value, ok := c.DefaultSQL(); ok {
+		clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", name, value))
+	} else {
+		clauses = append(clauses, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", name))
+	}
+
+	return strings.Join(clauses, ", "), nil
+}
+
+// Name XXX
+func (i Index) Name() string {
+	return i.name
+}
+
+// Columns XXX
+func (i Index) Columns() []string {
+	return i.columns
+}
+
+// ToSQL return index sql string without table
+func (i Index) ToSQL() string {
+	return fmt.Sprintf("INDEX %s (%s)", quote(i.name), quoteColumns(i.columns))
+}
+
+// CreateSQL return create index statement for table
+func (i Index) CreateSQL(table string) string {
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", quote(i.name), table, quoteColumns(i.columns))
+}
+
+// Name XXX
+func (ui UniqueIndex) Name() string {
+	return ui.name
+}
+
+// Columns XXX
+func (ui UniqueIndex) Columns() []string {
+	return ui.columns
+}
+
+// ToSQL return unique index sql string without table
+func (ui UniqueIndex) ToSQL() string {
+	return fmt.Sprintf("UNIQUE INDEX %s (%s)", quote(ui.name), quoteColumns(ui.columns))
+}
+
+// CreateSQL return create unique index statement for table
+func (ui UniqueIndex) CreateSQL(table string) string {
+	return fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s)", quote(ui.name), table, quoteColumns(ui.columns))
+}
+
+// Columns XXX
+func (pk PrimaryKey) Columns() []string {
+	return pk.columns
+}
+
+// ToSQL return primary key sql string
+func (pk PrimaryKey) ToSQL() string {
+	return fmt.Sprintf("PRIMARY KEY (%s)", quoteColumns(pk.columns))
+}
+
+// Name XXX
+func (fk ForeignKey) Name() string {
+	return fk.name
+}
+
+// ForeignColumns XXX
+func (fk ForeignKey) ForeignColumns() []string {
+	return fk.foreignColumns
+}
+
+// ReferenceTableName XXX
+func (fk ForeignKey) ReferenceTableName() string {
+	return fk.referenceTableName
+}
+
+// ReferenceColumns XXX
+func (fk ForeignKey) ReferenceColumns() []string {
+	return fk.referenceColumns
+}
+
+// UpdateOption XXX
+func (fk ForeignKey) UpdateOption() string {
+	return fk.updateOption
+}
+
+// DeleteOption XXX
+func (fk ForeignKey) DeleteOption() string {
+	return fk.deleteOption
+}
+
+// ToSQL return foreign key sql string
+func (fk ForeignKey) ToSQL() string {
+	sql := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteColumns(fk.foreignColumns),
+		quote(fk.referenceTableName),
+		quoteColumns(fk.referenceColumns))
+	if fk.deleteOption != "" {
+		sql = sql + fmt.Sprintf(" ON DELETE %s", fk.deleteOption)
+	}
+	if fk.updateOption != "" {
+		sql = sql + fmt.Sprintf(" ON UPDATE %s", fk.updateOption)
+	}
+	if fk.name != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(fk.name)) + sql
+	}
+	return sql
+}
+
+// Name XXX
+func (c Check) Name() string {
+	return c.name
+}
+
+// Expr XXX
+func (c Check) Expr() string {
+	return c.expr
+}
+
+// ToSQL XXX
+func (c Check) ToSQL() string {
+	sql := fmt.Sprintf("CHECK (%s)", c.expr)
+	if c.name != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(c.name)) + sql
+	}
+	return sql
+}
+
+// AddIndex XXX
+func AddIndex(idxName string, columns ...string) Index {
+	return Index{
+		name:    idxName,
+		columns: columns,
+	}
+}
+
+// AddUniqueIndex XXX
+func AddUniqueIndex(idxName string, columns ...string) UniqueIndex {
+	return UniqueIndex{
+		name:    idxName,
+		columns: columns,
+	}
+}
+
+// AddPrimaryKey XXX
+func AddPrimaryKey(columns ...string) PrimaryKey {
+	return PrimaryKey{
+		columns: columns,
+	}
+}
+
+// AddCheck adds CHECK (expr) constraint, which is unnamed if name is empty
+func AddCheck(name, expr string) Check {
+	return Check{
+		name: name,
+		expr: expr,
+	}
+}
+
+// AddForeignKey XXX
+func AddForeignKey(foreignColumns, referenceColumns []string, referenceTableName string, option ...ForeignKeyOption) ForeignKey {
+	foreignKey := ForeignKey{
+		foreignColumns:     foreignColumns,
+		referenceTableName: referenceTableName,
+		referenceColumns:   referenceColumns,
+	}
+
+	for _, o := range option {
+		if o != nil {
+			o.Apply(&foreignKey)
+		}
+	}
+
+	return foreignKey
+}
+
+func varchar(size uint64) string {
+	if size == 0 {
+		return "TEXT"
+	}
+
+	return fmt.Sprintf("VARCHAR(%d)", size)
+}
+
+func numeric(size uint64) string {
+	if size == 0 {
+		// NUMERIC without precision stores any value exactly
+		return "NUMERIC"
+	}
+
+	return fmt.Sprintf("NUMERIC(%d,0)", size)
+}
+
+func timestamptz(size uint64) string {
+	if size == 0 {
+		return "TIMESTAMPTZ"
+	}
+
+	return fmt.Sprintf("TIMESTAMPTZ(%d)", size)
+}
+
+func quoteColumns(columns []string) string {
+	var columnsStr []string
+	for _, c := range columns {
+		columnsStr = append(columnsStr, quote(c))
+	}
+
+	return strings.Join(columnsStr, ", ")
+}
+
+func quote(s string) string {
+	return fmt.Sprintf(`"%s"`, s)
+}
+
+func quoteString(s string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+}
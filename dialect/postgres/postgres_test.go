@@ -0,0 +1,272 @@
+package postgres
+
+import (
+	"testing"
+)
+
+func TestToSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	testcases := []struct {
+		typeName string
+		size     uint64
+		output   string
+	}{
+		{"bool", 0, "BOOLEAN"},
+		{"*bool", 0, "BOOLEAN"},
+		{"sql.NullBool", 0, "BOOLEAN"},
+		{"int8", 0, "SMALLINT"},
+		{"int16", 0, "SMALLINT"},
+		{"int32", 0, "INTEGER"},
+		{"sql.NullInt32", 0, "INTEGER"},
+		{"int64", 0, "BIGINT"},
+		{"sql.NullInt64", 0, "BIGINT"},
+		{"uint8", 0, "SMALLINT"},
+		{"uint16", 0, "INTEGER"},
+		{"uint32", 0, "BIGINT"},
+		{"uint64", 0, "BIGINT"},
+		{"float32", 0, "REAL"},
+		{"float64", 0, "DOUBLE PRECISION"},
+		{"sql.NullFloat64", 0, "DOUBLE PRECISION"},
+		{"string", 0, "TEXT"},
+		{"*string", 0, "TEXT"},
+		{"sql.NullString", 0, "TEXT"},
+		{"string", 10, "VARCHAR(10)"},
+		{"[]uint8", 0, "BYTEA"},
+		{"sql.RawBytes", 0, "BYTEA"},
+		{"text", 0, "TEXT"},
+		{"longtext", 0, "TEXT"},
+		{"blob", 0, "BYTEA"},
+		{"time", 0, "TIME"},
+		{"time.Time", 0, "TIMESTAMPTZ"},
+		{"time.Time", 6, "TIMESTAMPTZ(6)"},
+		{"sql.NullTime", 0, "TIMESTAMPTZ"},
+		{"date", 0, "DATE"},
+		{"json.RawMessage", 0, "JSONB"},
+	}
+
+	for _, tc := range testcases {
+		sql, err := pg.ToSQL(tc.typeName, tc.size)
+		if err != nil {
+			t.Fatalf("error %s to sql. %s", tc.typeName, err)
+		}
+		if sql != tc.output {
+			t.Fatalf("error %s to sql %s. but result %s", tc.typeName, tc.output, sql)
+		}
+	}
+
+	if _, err := pg.ToSQL("mypkg.Status", 0); err == nil {
+		t.Fatal("error unknown type to sql")
+	}
+}
+
+func TestDecimalToSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	if sql, err := pg.ToSQL("decimal.Decimal", 0); sql != "NUMERIC" || err != nil {
+		t.Fatalf("error decimal to sql. result: %s, %v", sql, err)
+	}
+	if sql, err := pg.DecimalToSQL("decimal.Decimal", 12, 2); sql != "NUMERIC(12,2)" || err != nil {
+		t.Fatalf("error decimal to sql. result: %s, %v", sql, err)
+	}
+	if _, err := pg.DecimalToSQL("float64", 12, 2); err == nil {
+		t.Fatal("error precision of float64 to sql")
+	}
+}
+
+func TestEnumToSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	sql, err := pg.EnumToSQL("status", "enum", 16, []string{"draft", "published"})
+	if err != nil || sql != `VARCHAR(16) CHECK ("status" IN ('draft', 'published'))` {
+		t.Fatalf("error enum to sql. result: %s, %v", sql, err)
+	}
+	if sql, err := pg.EnumTypeSQL("enum", 16); err != nil || sql != "VARCHAR(16)" {
+		t.Fatalf("error enum type sql. result: %s, %v", sql, err)
+	}
+	if sql := pg.AlterEnumSQL(`"entry"`, enumColumn{"status", []string{"draft"}}); sql != `DROP CONSTRAINT IF EXISTS "entry_status_check", ADD CONSTRAINT "entry_status_check" CHECK ("status" IN ('draft'))` {
+		t.Fatalf("error alter enum check. result: %s", sql)
+	}
+	if sql := pg.AlterEnumSQL(`"entry"`, enumColumn{"status", nil}); sql != `DROP CONSTRAINT IF EXISTS "entry_status_check"` {
+		t.Fatalf("error drop enum check. result: %s", sql)
+	}
+}
+
+type enumColumn struct {
+	name   string
+	values []string
+}
+
+func (c enumColumn) Name() string {
+	return c.name
+}
+
+func (c enumColumn) EnumValues() []string {
+	return c.values
+}
+
+func TestGeneratedSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	if sql := pg.GeneratedSQL("price * quantity", true); sql != "GENERATED ALWAYS AS (price * quantity) STORED" {
+		t.Fatal("[error] generated column", sql)
+	}
+	if sql := pg.GeneratedSQL("price * quantity", false); sql != "GENERATED ALWAYS AS (price * quantity) VIRTUAL" {
+		t.Fatal("[error] generated column", sql)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	column := "id"
+
+	if quote(column) != `"id"` {
+		t.Fatalf("error %s quote. result:%s ", column, quote(column))
+	}
+}
+
+func TestAuotIncrement(t *testing.T) {
+	pg := PostgreSQL{}
+	if pg.AutoIncrement() != autoIncrement {
+		t.Fatalf("error auto increament: %s. result:%s", autoIncrement, pg.AutoIncrement())
+	}
+}
+
+func TestAddIndex(t *testing.T) {
+	index := AddIndex("player_entry_id_idx", "player_id", "entry_id")
+	if index.ToSQL() != `INDEX "player_entry_id_idx" ("player_id", "entry_id")` {
+		t.Fatal("[error] parse player_entry_id_idx", index.ToSQL())
+	}
+	if index.CreateSQL(`"comment"`) != `CREATE INDEX "player_entry_id_idx" ON "comment" ("player_id", "entry_id")` {
+		t.Fatal("[error] create player_entry_id_idx", index.CreateSQL(`"comment"`))
+	}
+}
+
+func TestAddUniqIndex(t *testing.T) {
+	uniqIndex := AddUniqueIndex("player_id_idx", "player_id")
+	if uniqIndex.ToSQL() != `UNIQUE INDEX "player_id_idx" ("player_id")` {
+		t.Fatal("[error] parse unique player_id_idx", uniqIndex.ToSQL())
+	}
+	if uniqIndex.CreateSQL(`"comment"`) != `CREATE UNIQUE INDEX "player_id_idx" ON "comment" ("player_id")` {
+		t.Fatal("[error] create unique player_id_idx", uniqIndex.CreateSQL(`"comment"`))
+	}
+}
+
+func TestAddPrimaryKey(t *testing.T) {
+	pk := AddPrimaryKey("id", "created_at")
+	if pk.ToSQL() != `PRIMARY KEY ("id", "created_at")` {
+		t.Fatal("[error] parse primary key", pk.ToSQL())
+	}
+}
+
+func TestAddForeignKey(t *testing.T) {
+	fk := AddForeignKey([]string{"player_id"}, []string{"id"}, "player")
+	if fk.ToSQL() != `FOREIGN KEY ("player_id") REFERENCES "player" ("id")` {
+		t.Fatal("[error] parse foreign key", fk.ToSQL())
+	}
+
+	fk = AddForeignKey([]string{"player_id"}, []string{"id"}, "player", WithDeleteForeignKeyOption(ForeignKeyOptionCascade), WithUpdateForeignKeyOption(ForeignKeyOptionNoAction))
+	if fk.ToSQL() != `FOREIGN KEY ("player_id") REFERENCES "player" ("id") ON DELETE CASCADE` {
+		t.Fatal("[error] parse foreign key", fk.ToSQL())
+	}
+}
+
+type alterColumn struct {
+	name      string
+	typeSQL   string
+	null      bool
+	defaultOK bool
+	value     string
+	generated string
+	auto      bool
+}
+
+func (c alterColumn) Name() string {
+	return c.name
+}
+
+func (c alterColumn) TypeSQL() (string, error) {
+	return c.typeSQL, nil
+}
+
+func (c alterColumn) Null() bool {
+	return c.null
+}
+
+func (c alterColumn) DefaultSQL() (string, bool) {
+	return c.value, c.defaultOK
+}
+
+func (c alterColumn) GeneratedExpr() (string, bool) {
+	return c.generated, c.generated != ""
+}
+
+func (c alterColumn) AutoIncrement() bool {
+	return c.auto
+}
+
+func TestAlterColumnSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	testcases := []struct {
+		column alterColumn
+		output string
+	}{
+		{alterColumn{name: "name", typeSQL: "VARCHAR(100)"}, `ALTER COLUMN "name" TYPE VARCHAR(100), ALTER COLUMN "name" SET NOT NULL, ALTER COLUMN "name" DROP DEFAULT`},
+		{alterColumn{name: "public", typeSQL: "BOOLEAN", null: true, defaultOK: true, value: "false"}, `ALTER COLUMN "public" TYPE BOOLEAN, ALTER COLUMN "public" DROP NOT NULL, ALTER COLUMN "public" SET DEFAULT false`},
+		{alterColumn{name: "id", typeSQL: "BIGINT", auto: true}, `ALTER COLUMN "id" TYPE BIGINT, ALTER COLUMN "id" SET NOT NULL`},
+		{alterColumn{name: "total", typeSQL: "BIGINT", generated: "price * quantity"}, `ALTER COLUMN "total" TYPE BIGINT, ALTER COLUMN "total" SET NOT NULL, ALTER COLUMN "total" SET EXPRESSION AS (price * quantity)`},
+		{alterColumn{name: "named", typeSQL: "BOOLEAN", generated: "name IS NOT NULL"}, `ALTER COLUMN "named" TYPE BOOLEAN, ALTER COLUMN "named" SET NOT NULL, ALTER COLUMN "named" SET EXPRESSION AS (name IS NOT NULL)`},
+	}
+
+	for _, tc := range testcases {
+		sql, err := pg.AlterColumnSQL(tc.column)
+		if err != nil || sql != tc.output {
+			t.Fatalf("error alter column %s. result:%s, %v", tc.column.name, sql, err)
+		}
+	}
+
+	if _, err := pg.AlterColumnSQL("name"); err == nil {
+		t.Fatal("error alter column which is not AlterColumn")
+	}
+}
+
+func TestAddNamedForeignKey(t *testing.T) {
+	fk := AddForeignKey([]string{"player_id"}, []string{"id"}, "player", WithForeignKeyName("player_fk"))
+	if fk.ToSQL() != `CONSTRAINT "player_fk" FOREIGN KEY ("player_id") REFERENCES "player" ("id")` {
+		t.Fatal("[error] parse foreign key", fk.ToSQL())
+	}
+	if fk.Name() != "player_fk" {
+		t.Fatal("[error] foreign key name", fk.Name())
+	}
+}
+
+func TestAddCheck(t *testing.T) {
+	check := AddCheck("", "price >= 0")
+	if check.ToSQL() != "CHECK (price >= 0)" {
+		t.Fatal("[error] parse check", check.ToSQL())
+	}
+
+	check = AddCheck("price_positive", "price >= 0")
+	if check.ToSQL() != `CONSTRAINT "price_positive" CHECK (price >= 0)` {
+		t.Fatal("[error] parse check", check.ToSQL())
+	}
+}
+
+func TestTableOptionsSQL(t *testing.T) {
+	pg := PostgreSQL{}
+
+	if sql, err := pg.TableOptionsSQL(nil); err != nil || sql != "" {
+		t.Fatal("[error] table options", sql, err)
+	}
+
+	options := map[string]string{"fillfactor": "70", "toast.autovacuum_enabled": "false", TableOptionTablespace: "fast"}
+	if sql, err := pg.TableOptionsSQL(options); err != nil || sql != ` WITH (fillfactor=70, toast.autovacuum_enabled=false) TABLESPACE "fast"` {
+		t.Fatal("[error] table options", sql, err)
+	}
+
+	for _, name := range []string{"ROW_FORMAT", "toast.fillfactor", "toast.autovacuum_analyze_threshold"} {
+		if _, err := pg.TableOptionsSQL(map[string]string{name: "1"}); err == nil {
+			t.Fatal("[error] unknown table option", name)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kayac/ddl-maker/dialect/typemap"
+)
+
+const (
+	autoIncrement = "PRIMARY KEY AUTOINCREMENT"
+)
+
+// The names of table options, whose values are ignored
+const (
+	TableOptionWithoutRowID = "WITHOUT ROWID"
+	TableOptionStrict       = "STRICT"
+)
+
+// SQLite XXX
+type SQLite struct {
+	types typemap.TypeMap
+}
+
+// PrimaryKey is the primary key definition of any dialect
+type PrimaryKey interface {
+	Columns() []string
+}
+
+// ForeignKey is the foreign key definition of any dialect
+type ForeignKey interface {
+	Name() string
+	ForeignColumns() []string
+	ReferenceTableName() string
+	ReferenceColumns() []string
+	UpdateOption() string
+	DeleteOption() string
+}
+
+// Check is the check constraint of any dialect
+type Check interface {
+	Name() string
+	Expr() string
+}
+
+// AutoIncrementColumn is the column which reports whether it is declared with auto tag
+type AutoIncrementColumn interface {
+	Name() string
+	AutoIncrement() bool
+}
+
+// Index is the index definition of any dialect
+type Index interface {
+	Name() string
+	Columns() []string
+	ToSQL() string
+}
+
+// HeaderTemplate XXX
+func (sqlite SQLite) HeaderTemplate() string {
+	return `PRAGMA foreign_keys=OFF;
+`
+}
+
+// FooterTemplate XXX
+func (sqlite SQLite) FooterTemplate() string {
+	return `PRAGMA foreign_keys=ON;
+`
+}
+
+// TableTemplate XXX
+// An auto column is declared as INTEGER PRIMARY KEY AUTOINCREMENT,
+// so the table level PRIMARY KEY is omitted for such tables.
+// SQLite has no inline INDEX definition, so indexes are created after the table.
+// SQLite has no COMMENT clause either, so the comments of the table and columns are not written.
+func (sqlite SQLite) TableTemplate() string {
+	return `
+DROP TABLE IF EXISTS {{ .Name }};
+
+CREATE TABLE {{ .Name }} (
+    {{- $auto := false }}
+    {{- range $i, $column := .Columns }}
+    {{- if $.Dialect.IsAutoIncrement $column $.PrimaryKey }}{{ $auto = true }}{{ end }}
+    {{- if $i }},{{ end }}
+    {{ $column.ToSQL }}
+    {{- end }}
+    {{- if not $auto }},
+    {{ $.Dialect.PrimaryKeySQL .PrimaryKey }}
+    {{- end }}
+    {{- range .ForeignKeys.Sort }},
+    {{ $.Dialect.ForeignKeySQL . }}
+    {{- end }}
+    {{- range .Checks.Sort }},
+    {{ $.Dialect.CheckSQL . }}
+    {{- end }}
+){{ .Dialect.TableOptionsSQL .TableOptions }};
+{{ range .Indexes.Sort -}}
+{{ $.Dialect.CreateIndexSQL $.Name . }};
+{{ end }}
+`
+}
+
+// AlterTableTemplate XXX
+// SQLite can not alter columns or constraints of an existing table,
+// such changes are written as comments to rebuild the table by hand.
+func (sqlite SQLite) AlterTableTemplate() string {
+	return `
+{{ range .DropForeignKeys -}}
+-- SQLite can not drop a foreign key, rebuild {{ $.Name }}: {{ $.Dialect.ForeignKeySQL . }}
+{{ end -}}
+{{ range .DropChecks -}}
+-- SQLite can not drop a check, rebuild {{ $.Name }}: {{ $.Dialect.CheckSQL . }}
+{{ end -}}
+{{ range .DropIndexes -}}
+DROP INDEX {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ with .DropPrimaryKey -}}
+-- SQLite can not drop a primary key, rebuild {{ $.Name }}: {{ $.Dialect.PrimaryKeySQL . }}
+{{ end -}}
+{{ range .DropColumns -}}
+ALTER TABLE {{ $.Name }} DROP COLUMN {{ $.Dialect.Quote .Name }};
+{{ end -}}
+{{ range .AddColumns -}}
+ALTER TABLE {{ $.Name }} ADD COLUMN {{ .ToSQL }};
+{{ end -}}
+{{ range .ModifyColumns -}}
+-- SQLite can not modify a column, rebuild {{ $.Name }}: {{ .ToSQL }}
+{{ end -}}
+{{ with .AddPrimaryKey -}}
+-- SQLite can not add a primary key, rebuild {{ $.Name }}: {{ $.Dialect.PrimaryKeySQL . }}
+{{ end -}}
+{{ range .AddIndexes -}}
+{{ $.Dialect.CreateIndexSQL $.Name . }};
+{{ end -}}
+{{ range .AddChecks -}}
+-- SQLite can not add a check, rebuild {{ $.Name }}: {{ $.Dialect.CheckSQL . }}
+{{ end -}}
+{{ range .AddForeignKeys -}}
+-- SQLite can not add a foreign key, rebuild {{ $.Name }}: {{ $.Dialect.ForeignKeySQL . }}
+{{ end }}
+`
+}
+
+// DropTableTemplate XXX
+func (sqlite SQLite) DropTableTemplate() string {
+	return `
+DROP TABLE IF EXISTS {{ .Name }};
+
+`
+}
+
+// TableOptionsSQL returns WITHOUT ROWID and STRICT of a table, which starts with a space if any
+func (sqlite SQLite) TableOptionsSQL(options map[string]string) (string, error) {
+	var sql []string
+	for _, name := range []string{TableOptionWithoutRowID, TableOptionStrict} {
+		if _, ok := options[name]; ok {
+			sql = append(sql, name)
+		}
+	}
+	if len(sql) != len(options) {
+		return "", fmt.Errorf("unknown table option in %v", options)
+	}
+	if len(sql) == 0 {
+		return "", nil
+	}
+
+	return " " + strings.Join(sql, ", "), nil
+}
+
+// RegisterType maps the Go type t, which is a type name such as "uuid.UUID", a reflect.Type or a value of the type,
+// to the sql type returned by f. Registered types are used before the built-in types.
+func (sqlite *SQLite) RegisterType(t interface{}, f typemap.TypeFunc) error {
+	if sqlite.types == nil {
+		sqlite.types = make(typemap.TypeMap)
+	}
+
+	return sqlite.types.Register(t, f)
+}
+
+// ToSQL convert sqlite sql string from typeName and size
+// Declared types are chosen from the SQLite type affinities.
+// BOOLEAN, DATETIME and DATE have NUMERIC affinity, and let drivers such as go-sqlite3 scan them into Go values.
+func (sqlite SQLite) ToSQL(typeName string, size uint64) (string, error) {
+	if sql, ok := sqlite.types.ToSQL(typeName, size); ok {
+		return sql, nil
+	}
+
+	switch typeName {
+	case "int8", "*int8", "int16", "*int16", "int32", "*int32", "sql.NullInt32", "int64", "*int64", "sql.NullInt64",
+		"uint8", "*uint8", "uint16", "*uint16", "uint32", "*uint32", "uint64", "*uint64":
+		return "INTEGER", nil
+	case "float32", "*float32", "float64", "*float64", "sql.NullFloat64":
+		return "REAL", nil
+	case "string", "*string", "sql.NullString", "tinytext", "text", "mediumtext", "longtext", "time":
+		return "TEXT", nil
+	case "[]uint8", "sql.RawBytes", "tinyblob", "blob", "mediumblob", "longblob", "geometry":
+		return "BLOB", nil
+	case "bool", "*bool", "sql.NullBool":
+		return "BOOLEAN", nil
+	case "time.Time", "*time.Time", "mysql.NullTime", "sql.NullTime":
+		return "DATETIME", nil
+	case "date":
+		return "DATE", nil
+	case "decimal", "numeric", "decimal.Decimal", "*decimal.Decimal", "decimal.NullDecimal", "big.Rat", "*big.Rat":
+		return "NUMERIC", nil
+	case "json.RawMessage", "*json.RawMessage":
+		return "TEXT", nil
+	default:
+		return "", fmt.Errorf("%s is not match", typeName)
+	}
+}
+
+// CollateSQL returns the column type with COLLATE such as BINARY, NOCASE or RTRIM, which is put before the CHECK constraint of enum values.
+// The character set of a column is not supported, which is the encoding of the database.
+func (sqlite SQLite) CollateSQL(typeSQL, charset, collate string) (string, error) {
+	if charset != "" {
+		return "", fmt.Errorf("character set of a column is not supported")
+	}
+	if collate == "" {
+		return typeSQL, nil
+	}
+
+	check := ""
+	if i := strings.Index(typeSQL, " CHECK ("); i >= 0 {
+		typeSQL, check = typeSQL[:i], typeSQL[i:]
+	}
+
+	return fmt.Sprintf("%s COLLATE %s%s", typeSQL, collate, check), nil
+}
+
+// DecimalToSQL XXX
+// SQLite stores a decimal type with NUMERIC affinity, and ignores the precision and scale.
+func (sqlite SQLite) DecimalToSQL(typeName string, precision, scale uint64) (string, error) {
+	sql, err := sqlite.ToSQL(typeName, 0)
+	if err != nil {
+		return "", err
+	}
+	if sql != "NUMERIC" {
+		return "", fmt.Errorf("%s does not support precision", typeName)
+	}
+
+	return sql, nil
+}
+
+// SchemaIndexName XXX
+// An index of SQLite is an object of the schema, so its name must be unique among all tables.
+func (sqlite SQLite) SchemaIndexName() bool {
+	return true
+}
+
+// AllowForwardReference XXX
+// SQLite checks foreign keys when rows are written.
+func (sqlite SQLite) AllowForwardReference() bool {
+	return true
+}
+
+// EnumToSQL returns the type of typeName with a CHECK constraint of the values.
+// "enum" is stored as a string, and "set" is not supported.
+func (sqlite SQLite) EnumToSQL(column, typeName string, size uint64, values []string) (string, error) {
+	switch typeName {
+	case "set":
+		return "", fmt.Errorf("set is not supported")
+	case "enum":
+		typeName = "string"
+	}
+	sql, err := sqlite.ToSQL(typeName, size)
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteString(v))
+	}
+
+	return fmt.Sprintf("%s CHECK (%s IN (%s))", sql, quote(column), strings.Join(quoted, ", ")), nil
+}
+
+// QuoteString returns s as a string literal
+func (sqlite SQLite) QuoteString(s string) string {
+	return quoteString(s)
+}
+
+// GeneratedSQL returns the clause of a generated column, which is VIRTUAL unless stored
+func (sqlite SQLite) GeneratedSQL(expr string, stored bool) string {
+	if stored {
+		return fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", expr)
+	}
+
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) VIRTUAL", expr)
+}
+
+// Quote XXX
+func (sqlite SQLite) Quote(s string) string {
+	return quote(s)
+}
+
+// AutoIncrement XXX
+func (sqlite SQLite) AutoIncrement() string {
+	return autoIncrement
+}
+
+// IsAutoIncrement reports whether column is declared with auto tag, which is INTEGER PRIMARY KEY AUTOINCREMENT.
+// It returns an error if the primary key pk has other columns, since the column is the only primary key column.
+func (sqlite SQLite) IsAutoIncrement(column interface{}, pk PrimaryKey) (bool, error) {
+	c, ok := column.(AutoIncrementColumn)
+	if !ok || !c.AutoIncrement() {
+		return false, nil
+	}
+	if pk != nil && (len(pk.Columns()) != 1 || pk.Columns()[0] != c.Name()) {
+		return false, fmt.Errorf("auto column %s must be the only column of the primary key (%s)", c.Name(), strings.Join(pk.Columns(), ", "))
+	}
+
+	return true, nil
+}
+
+// PrimaryKeySQL return primary key sql string
+func (sqlite SQLite) PrimaryKeySQL(pk PrimaryKey) string {
+	return fmt.Sprintf("PRIMARY KEY (%s)", quoteColumns(pk.Columns()))
+}
+
+// ForeignKeySQL return foreign key sql string
+func (sqlite SQLite) ForeignKeySQL(fk ForeignKey) string {
+	sql := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteColumns(fk.ForeignColumns()),
+		quote(fk.ReferenceTableName()),
+		quoteColumns(fk.ReferenceColumns()))
+	if fk.DeleteOption() != "" {
+		sql = sql + fmt.Sprintf(" ON DELETE %s", fk.DeleteOption())
+	}
+	if fk.UpdateOption() != "" {
+		sql = sql + fmt.Sprintf(" ON UPDATE %s", fk.UpdateOption())
+	}
+	if fk.Name() != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(fk.Name())) + sql
+	}
+	return sql
+}
+
+// CheckSQL return check constraint sql string
+func (sqlite SQLite) CheckSQL(check Check) string {
+	sql := fmt.Sprintf("CHECK (%s)", check.Expr())
+	if check.Name() != "" {
+		sql = fmt.Sprintf("CONSTRAINT %s ", quote(check.Name())) + sql
+	}
+	return sql
+}
+
+// CreateIndexSQL return create index statement for table
+// Unique indexes are detected from the index sql, FULLTEXT and SPATIAL indexes become plain indexes.
+func (sqlite SQLite) CreateIndexSQL(table string, index Index) string {
+	create := "CREATE INDEX"
+	if strings.HasPrefix(index.ToSQL(), "UNIQUE") {
+		create = "CREATE UNIQUE INDEX"
+	}
+
+	return fmt.Sprintf("%s %s ON %s (%s)", create, quote(index.Name()), table, quoteColumns(index.Columns()))
+}
+
+func quoteColumns(columns []string) string {
+	var columnsStr []string
+	for _, c := range columns {
+		columnsStr = append(columnsStr, quote(c))
+	}
+
+	return strings.Join(columnsStr, ", ")
+}
+
+func quote(s string) string {
+	return fmt.Sprintf(`"%s"`, s)
+}
+
+func quoteString(s string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+}
@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+func TestToSQL(t *testing.T) {
+	s := SQLite{}
+
+	testcases := []struct {
+		typeName string
+		size     uint64
+		output   string
+	}{
+		{"bool", 0, "BOOLEAN"},
+		{"sql.NullBool", 0, "BOOLEAN"},
+		{"int8", 0, "INTEGER"},
+		{"int32", 0, "INTEGER"},
+		{"sql.NullInt64", 0, "INTEGER"},
+		{"uint64", 0, "INTEGER"},
+		{"*uint64", 0, "INTEGER"},
+		{"float32", 0, "REAL"},
+		{"sql.NullFloat64", 0, "REAL"},
+		{"string", 0, "TEXT"},
+		{"string", 10, "TEXT"},
+		{"sql.NullString", 0, "TEXT"},
+		{"text", 0, "TEXT"},
+		{"[]uint8", 0, "BLOB"},
+		{"blob", 0, "BLOB"},
+		{"time.Time", 0, "DATETIME"},
+		{"sql.NullTime", 0, "DATETIME"},
+		{"date", 0, "DATE"},
+		{"json.RawMessage", 0, "TEXT"},
+	}
+
+	for _, tc := range testcases {
+		sql, err := s.ToSQL(tc.typeName, tc.size)
+		if err != nil {
+			t.Fatalf("error %s to sql. %s", tc.typeName, err)
+		}
+		if sql != tc.output {
+			t.Fatalf("error %s to sql %s. but result %s", tc.typeName, tc.output, sql)
+		}
+	}
+
+	if _, err := s.ToSQL("mypkg.Status", 0); err == nil {
+		t.Fatal("error unknown type to sql")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	column := "id"
+
+	if quote(column) != `"id"` {
+		t.Fatalf("error %s quote. result:%s ", column, quote(column))
+	}
+}
+
+type autoColumn struct {
+	name string
+	auto bool
+}
+
+func (c autoColumn) Name() string {
+	return c.name
+}
+
+func (c autoColumn) AutoIncrement() bool {
+	return c.auto
+}
+
+func TestIsAutoIncrement(t *testing.T) {
+	s := SQLite{}
+	if auto, err := s.IsAutoIncrement(autoColumn{name: "id", auto: true}, mysql.AddPrimaryKey("id")); err != nil || !auto {
+		t.Fatal("error auto increment column", err)
+	}
+	if auto, err := s.IsAutoIncrement(autoColumn{name: "id"}, mysql.AddPrimaryKey("id", "created_at")); err != nil || auto {
+		t.Fatal("error not auto increment column", err)
+	}
+	if auto, err := s.IsAutoIncrement(`"id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT`, mysql.AddPrimaryKey("id")); err != nil || auto {
+		t.Fatal("error auto increment column sql", err)
+	}
+	if _, err := s.IsAutoIncrement(autoColumn{name: "id", auto: true}, mysql.AddPrimaryKey("id", "created_at")); err == nil {
+		t.Fatal("error auto increment column of composite primary key")
+	}
+}
+
+func TestPrimaryKeySQL(t *testing.T) {
+	s := SQLite{}
+	pk := mysql.AddPrimaryKey("id", "created_at")
+	if s.PrimaryKeySQL(pk) != `PRIMARY KEY ("id", "created_at")` {
+		t.Fatal("[error] parse primary key", s.PrimaryKeySQL(pk))
+	}
+}
+
+func TestForeignKeySQL(t *testing.T) {
+	s := SQLite{}
+	fk := mysql.AddForeignKey([]string{"player_id"}, []string{"id"}, "player", mysql.WithDeleteForeignKeyOption(mysql.ForeignKeyOptionCascade))
+	if s.ForeignKeySQL(fk) != `FOREIGN KEY ("player_id") REFERENCES "player" ("id") ON DELETE CASCADE` {
+		t.Fatal("[error] parse foreign key", s.ForeignKeySQL(fk))
+	}
+}
+
+func TestCreateIndexSQL(t *testing.T) {
+	s := SQLite{}
+
+	index := mysql.AddIndex("player_entry_id_idx", "player_id", "entry_id")
+	if s.CreateIndexSQL(`"comment"`, index) != `CREATE INDEX "player_entry_id_idx" ON "comment" ("player_id", "entry_id")` {
+		t.Fatal("[error] create index", s.CreateIndexSQL(`"comment"`, index))
+	}
+
+	uniqIndex := mysql.AddUniqueIndex("player_id_idx", "player_id")
+	if s.CreateIndexSQL(`"comment"`, uniqIndex) != `CREATE UNIQUE INDEX "player_id_idx" ON "comment" ("player_id")` {
+		t.Fatal("[error] create unique index", s.CreateIndexSQL(`"comment"`, uniqIndex))
+	}
+}
+
+func TestCheckSQL(t *testing.T) {
+	s := SQLite{}
+
+	check := mysql.AddCheck("price_positive", "price >= 0")
+	if s.CheckSQL(check) != `CONSTRAINT "price_positive" CHECK (price >= 0)` {
+		t.Fatal("[error] check", s.CheckSQL(check))
+	}
+}
+
+func TestEnumToSQL(t *testing.T) {
+	s := SQLite{}
+
+	sql, err := s.EnumToSQL("status", "enum", 0, []string{"draft", "it's"})
+	if err != nil || sql != `TEXT CHECK ("status" IN ('draft', 'it''s'))` {
+		t.Fatalf("error enum to sql. result: %s, %v", sql, err)
+	}
+	if _, err := s.EnumToSQL("tags", "set", 0, []string{"a"}); err == nil {
+		t.Fatal("error set to sql")
+	}
+}
+
+func TestTableOptionsSQL(t *testing.T) {
+	s := SQLite{}
+
+	sql, err := s.TableOptionsSQL(map[string]string{TableOptionStrict: "", TableOptionWithoutRowID: ""})
+	if err != nil || sql != " WITHOUT ROWID, STRICT" {
+		t.Fatalf("error table options. result: %s, %v", sql, err)
+	}
+	if _, err := s.TableOptionsSQL(map[string]string{"ENGINE": "InnoDB"}); err == nil {
+		t.Fatal("error unknown table option is accepted")
+	}
+}
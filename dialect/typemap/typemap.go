@@ -0,0 +1,60 @@
+// Package typemap is the registry of the sql types of Go types, which is shared by the dialects.
+package typemap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TypeFunc returns the sql type of a column from its size
+type TypeFunc func(size uint64) string
+
+// TypeMap maps a type name such as "uuid.UUID" to its sql type
+type TypeMap map[string]TypeFunc
+
+// Name returns the type name of t, which is a type name such as "uuid.UUID", a reflect.Type or a value of the type.
+// The name is the same as the one of a struct field, that is the package name and the type name.
+func Name(t interface{}) (string, error) {
+	switch v := t.(type) {
+	case nil:
+		return "", fmt.Errorf("nil is not supported")
+	case string:
+		if v == "" {
+			return "", fmt.Errorf("empty type name is not supported")
+		}
+		return v, nil
+	case reflect.Type:
+		return v.String(), nil
+	default:
+		return reflect.TypeOf(t).String(), nil
+	}
+}
+
+// Register maps the type t to the sql type returned by f
+func (m TypeMap) Register(t interface{}, f TypeFunc) error {
+	if f == nil {
+		return fmt.Errorf("nil TypeFunc is not supported")
+	}
+	name, err := Name(t)
+	if err != nil {
+		return err
+	}
+	m[name] = f
+
+	return nil
+}
+
+// ToSQL returns the sql type of typeName if it is registered.
+// A pointer type such as "*uuid.UUID" uses the registered type of its element type.
+func (m TypeMap) ToSQL(typeName string, size uint64) (string, bool) {
+	f, ok := m[typeName]
+	if !ok {
+		f, ok = m[strings.TrimPrefix(typeName, "*")]
+	}
+	if !ok {
+		return "", false
+	}
+
+	return f(size), true
+}
@@ -0,0 +1,58 @@
+package typemap
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	cases := []struct {
+		t    interface{}
+		name string
+	}{
+		{"uuid.UUID", "uuid.UUID"},
+		{reflect.TypeOf(time.Time{}), "time.Time"},
+		{time.Time{}, "time.Time"},
+		{&time.Time{}, "*time.Time"},
+		{[]byte{}, "[]uint8"},
+	}
+	for _, c := range cases {
+		name, err := Name(c.t)
+		if err != nil || name != c.name {
+			t.Fatalf("error Name(%v): %s, %v", c.t, name, err)
+		}
+	}
+
+	if _, err := Name(nil); err == nil {
+		t.Fatal("error Name(nil) is not error")
+	}
+	if _, err := Name(""); err == nil {
+		t.Fatal("error Name(\"\") is not error")
+	}
+}
+
+func TestToSQL(t *testing.T) {
+	m := make(TypeMap)
+	err := m.Register(time.Time{}, func(size uint64) string {
+		return fmt.Sprintf("TIMESTAMP(%d)", size)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sql, ok := m.ToSQL("time.Time", 6); !ok || sql != "TIMESTAMP(6)" {
+		t.Fatalf("error ToSQL: %s", sql)
+	}
+	if sql, ok := m.ToSQL("*time.Time", 3); !ok || sql != "TIMESTAMP(3)" {
+		t.Fatalf("error ToSQL pointer: %s", sql)
+	}
+	if _, ok := m.ToSQL("string", 0); ok {
+		t.Fatal("error ToSQL unregistered type")
+	}
+
+	if err := m.Register("string", nil); err == nil {
+		t.Fatal("error Register nil func")
+	}
+}
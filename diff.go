@@ -0,0 +1,616 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/pkg/errors"
+)
+
+// tableDiff is the difference of a table between two schemas
+type tableDiff struct {
+	name            string
+	addColumns      []dialect.Column
+	modifyColumns   []dialect.Column
+	dropColumns     []dialect.Column
+	addIndexes      dialect.Indexes
+	dropIndexes     dialect.Indexes
+	addForeignKeys  dialect.ForeignKeys
+	dropForeignKeys dialect.ForeignKeys
+	addPrimaryKey   dialect.PrimaryKey
+	dropPrimaryKey  dialect.PrimaryKey
+	addChecks       dialect.Checks
+	dropChecks      dialect.Checks
+	modifyEnums     []dialect.EnumColumn
+	dialect         dialect.Dialect
+}
+
+func newTableDiff(prev, current dialect.Table) (tableDiff, error) {
+	diff := tableDiff{
+		name:    current.Name(),
+		dialect: current.Dialect(),
+	}
+
+	prevCharset, prevCollate := tableCollation(prev)
+	charset, collate := tableCollation(current)
+	prevColumns := make(map[string]dialect.Column)
+	for _, c := range prev.Columns() {
+		prevColumns[c.Name()] = c
+	}
+	currentColumns := make(map[string]bool)
+	for _, c := range current.Columns() {
+		currentColumns[c.Name()] = true
+		pc, ok := prevColumns[c.Name()]
+		if !ok {
+			diff.addColumns = append(diff.addColumns, c)
+			continue
+		}
+
+		prevSQL, err := columnDefinition(pc, prev.Dialect(), prevCharset, prevCollate)
+		if err != nil {
+			return diff, errors.Wrapf(err, "error previous column %s", pc.Name())
+		}
+		sql, err := columnDefinition(c, current.Dialect(), charset, collate)
+		if err != nil {
+			return diff, errors.Wrapf(err, "error column %s", c.Name())
+		}
+		if prevSQL != sql || pc.Comment() != c.Comment() {
+			diff.modifyColumns = append(diff.modifyColumns, c)
+		}
+
+		enum, changed, err := enumDiff(pc, c)
+		if err != nil {
+			return diff, errors.Wrapf(err, "error enum values of column %s", c.Name())
+		}
+		if changed {
+			diff.modifyEnums = append(diff.modifyEnums, enum)
+		}
+	}
+	for _, c := range prev.Columns() {
+		if !currentColumns[c.Name()] {
+			diff.dropColumns = append(diff.dropColumns, c)
+		}
+	}
+
+	prevIndexes := make(map[string]dialect.Index)
+	for _, idx := range prev.Indexes() {
+		prevIndexes[idx.Name()] = idx
+	}
+	currentIndexes := make(map[string]dialect.Index)
+	for _, idx := range current.Indexes() {
+		currentIndexes[idx.Name()] = idx
+		pi, ok := prevIndexes[idx.Name()]
+		switch {
+		case !ok:
+			diff.addIndexes = append(diff.addIndexes, idx)
+		case pi.ToSQL() != idx.ToSQL():
+			diff.dropIndexes = append(diff.dropIndexes, pi)
+			diff.addIndexes = append(diff.addIndexes, idx)
+		}
+	}
+	for _, idx := range prev.Indexes() {
+		if _, ok := currentIndexes[idx.Name()]; !ok {
+			diff.dropIndexes = append(diff.dropIndexes, idx)
+		}
+	}
+
+	prevForeignKeys := make(map[string]bool)
+	for _, fk := range prev.ForeignKeys() {
+		prevForeignKeys[foreignKeyDefinition(fk)] = true
+	}
+	currentForeignKeys := make(map[string]bool)
+	for _, fk := range current.ForeignKeys() {
+		currentForeignKeys[foreignKeyDefinition(fk)] = true
+		if !prevForeignKeys[foreignKeyDefinition(fk)] {
+			diff.addForeignKeys = append(diff.addForeignKeys, fk)
+		}
+	}
+	for _, fk := range prev.ForeignKeys() {
+		if !currentForeignKeys[foreignKeyDefinition(fk)] {
+			diff.dropForeignKeys = append(diff.dropForeignKeys, fk)
+		}
+	}
+
+	if primaryKeyDefinition(prev.PrimaryKey()) != primaryKeyDefinition(current.PrimaryKey()) {
+		diff.dropPrimaryKey = prev.PrimaryKey()
+		diff.addPrimaryKey = current.PrimaryKey()
+	}
+
+	prevChecks := make(map[string]bool)
+	for _, c := range prev.Checks() {
+		prevChecks[checkDefinition(c)] = true
+	}
+	currentChecks := make(map[string]bool)
+	for _, c := range current.Checks() {
+		currentChecks[checkDefinition(c)] = true
+		if !prevChecks[checkDefinition(c)] {
+			diff.addChecks = append(diff.addChecks, c)
+		}
+	}
+	for _, c := range prev.Checks() {
+		if !currentChecks[checkDefinition(c)] {
+			diff.dropChecks = append(diff.dropChecks, c)
+		}
+	}
+
+	prevOptions, err := tableOptionsDefinition(prev, current.TableOptions())
+	if err != nil {
+		return diff, errors.Wrap(err, "error previous table options")
+	}
+	options, err := tableOptionsDefinition(current, current.TableOptions())
+	if err != nil {
+		return diff, errors.Wrap(err, "error table options")
+	}
+	if prevOptions != options {
+		return diff, fmt.Errorf("table options are changed from %q to %q, which can not be migrated", prevOptions, options)
+	}
+
+	prevPartitioning, partitioning := partitioningDefinition(prev.Partitioning()), partitioningDefinition(current.Partitioning())
+	if prevPartitioning != partitioning {
+		return diff, fmt.Errorf("partitioning is changed from %q to %q, which can not be migrated", prevPartitioning, partitioning)
+	}
+
+	return diff, nil
+}
+
+// generatedColumn is a column which writes the expression of a generated column, and the normalized one if normalize is true
+type generatedColumn interface {
+	dialect.Column
+	generatedSQL(normalize bool) string
+}
+
+// collatedColumn is a column which reports its sql type without the character set and the collation, and them
+type collatedColumn interface {
+	dialect.Column
+	collation() (string, string, string, error)
+}
+
+// columnDefinition returns the sql of c to be compared, in which
+//   - the expression of a generated column is normalized, so that ((`id` * 2)) written by MySQL is the same as id * 2
+//   - a numeric default is normalized, so that '0.00' written by MySQL is the same as 0
+//   - the character set and the collation are omitted if they are tableCharset and tableCollate
+func columnDefinition(c dialect.Column, d dialect.Dialect, tableCharset, tableCollate string) (string, error) {
+	sql, err := c.ToSQL()
+	if err != nil {
+		return "", err
+	}
+
+	if gc, ok := c.(generatedColumn); ok {
+		if generated := gc.generatedSQL(false); generated != "" {
+			sql = strings.Replace(sql, generated, gc.generatedSQL(true), 1)
+		}
+	}
+	if dc, ok := c.(interface{ DefaultSQL() (string, bool) }); ok {
+		if value, ok := dc.DefaultSQL(); ok {
+			sql = strings.Replace(sql, " DEFAULT "+value, " DEFAULT "+normalizeNumber(value), 1)
+		}
+	}
+	if cc, ok := c.(collatedColumn); ok {
+		typeSQL, charset, collate, err := cc.collation()
+		if err != nil {
+			return "", err
+		}
+		collator, ok := d.(dialect.Collator)
+		if !ok || (charset == "" && collate == "") {
+			return sql, nil
+		}
+		collated, err := collator.CollateSQL(typeSQL, charset, collate)
+		if err != nil {
+			return "", err
+		}
+		if strings.EqualFold(charset, tableCharset) {
+			charset = ""
+		}
+		if strings.EqualFold(collate, tableCollate) {
+			collate = ""
+		}
+		normalized, err := collator.CollateSQL(typeSQL, charset, collate)
+		if err != nil {
+			return "", err
+		}
+		sql = strings.Replace(sql, collated, normalized, 1)
+	}
+
+	return sql, nil
+}
+
+// normalizeNumber returns a numeric value without the plus sign, and the leading and trailing zeros, such as 0.5 of +00.50.
+// A value which is not a number is returned as it is.
+func normalizeNumber(value string) string {
+	if !numericDefaultRe.MatchString(value) || strings.ContainsAny(value, "eE") {
+		return value
+	}
+
+	sign := ""
+	switch value[0] {
+	case '-':
+		sign, value = "-", value[1:]
+	case '+':
+		value = value[1:]
+	}
+	integer, fraction := value, ""
+	if i := strings.Index(value, "."); i >= 0 {
+		integer, fraction = value[:i], strings.TrimRight(value[i+1:], "0")
+	}
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	value = integer
+	if fraction != "" {
+		value += "." + fraction
+	}
+	if value == "0" {
+		return value
+	}
+
+	return sign + value
+}
+
+// tableCollation returns the default character set and collation of the table of MySQL
+func tableCollation(t dialect.Table) (string, string) {
+	var charset string
+	switch d := t.Dialect().(type) {
+	case mysql.MySQL:
+		charset = d.Charset
+	case *mysql.MySQL:
+		charset = d.Charset
+	}
+	options := t.TableOptions()
+	if value, ok := options[mysql.TableOptionCharset]; ok {
+		charset = value
+	}
+
+	return charset, options[mysql.TableOptionCollate]
+}
+
+// enumColumn is a column with the enum values written by ToSQL
+type enumColumn struct {
+	dialect.Column
+	values []string
+}
+
+func (c enumColumn) EnumValues() []string {
+	return c.values
+}
+
+// enumDiff returns current with its enum values, and whether the values are changed from prev.
+// The columns which are not built from structs, such as the columns parsed from ddl, are not compared.
+func enumDiff(prev, current dialect.Column) (dialect.EnumColumn, bool, error) {
+	pc, ok := prev.(column)
+	if !ok {
+		return nil, false, nil
+	}
+	c, ok := current.(column)
+	if !ok {
+		return nil, false, nil
+	}
+
+	prevValues, err := pc.writtenEnumValues()
+	if err != nil {
+		return nil, false, err
+	}
+	values, err := c.writtenEnumValues()
+	if err != nil {
+		return nil, false, err
+	}
+	if equalValues(prevValues, values) {
+		return nil, false, nil
+	}
+
+	return enumColumn{Column: c, values: values}, true, nil
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// primaryKeyDefinition returns the columns of a primary key, which is empty for nil
+func primaryKeyDefinition(pk dialect.PrimaryKey) string {
+	if pk == nil {
+		return ""
+	}
+	return strings.Join(pk.Columns(), ",")
+}
+
+// checkDefinition returns the expression of a check constraint without its constraint name,
+// so that an expression written by mysqldump, such as ((`price` >= 0)), is the same as price >= 0.
+func checkDefinition(c dialect.Check) string {
+	return normalizeExpr(c.Expr())
+}
+
+// tableOptionsDefinition returns the table options of t in sql.
+// AUTO_INCREMENT of MySQL only applies to a created table, and COLLATE is the default of the character set
+// unless currentOptions declare it, so they are not compared.
+func tableOptionsDefinition(t dialect.Table, currentOptions dialect.TableOptions) (string, error) {
+	d, ok := t.Dialect().(interface {
+		TableOptionsSQL(options map[string]string) (string, error)
+	})
+	if !ok {
+		return "", nil
+	}
+
+	options := make(map[string]string, len(t.TableOptions()))
+	for name, value := range t.TableOptions() {
+		options[name] = value
+	}
+	switch t.Dialect().(type) {
+	case mysql.MySQL, *mysql.MySQL:
+		delete(options, mysql.TableOptionAutoIncrement)
+		if _, ok := currentOptions[mysql.TableOptionCollate]; !ok {
+			delete(options, mysql.TableOptionCollate)
+		}
+	}
+
+	return d.TableOptionsSQL(options)
+}
+
+// partitioningDefinition returns the partitioning in normalized tokens,
+// in which the storage engines of the partitions written by mysqldump are removed
+func partitioningDefinition(p dialect.Partitioning) string {
+	if p == nil {
+		return ""
+	}
+	tokens, err := tokenize(p.ToSQL())
+	if err != nil {
+		return p.ToSQL()
+	}
+
+	var ts []token
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].is("STORAGE") || tokens[i].is("ENGINE") {
+			for i+1 < len(tokens) && !tokens[i+1].is(",") && !tokens[i+1].is(")") {
+				i++
+			}
+			continue
+		}
+		ts = append(ts, tokens[i])
+	}
+
+	return joinTokens(ts)
+}
+
+// normalizeExpr returns expr in normalized tokens, in which the enclosing parentheses are removed
+func normalizeExpr(expr string) string {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return expr
+	}
+	for len(tokens) > 1 && tokens[0].is("(") && closeParen(tokens, 0) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	return joinTokens(tokens)
+}
+
+// joinTokens joins tokens by spaces, in which identifiers are unquoted, and keywords and identifiers are upper case
+func joinTokens(tokens []token) string {
+	texts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if t.kind == tokenWord || t.kind == tokenQuoted {
+			texts = append(texts, strings.ToUpper(t.text))
+			continue
+		}
+		texts = append(texts, t.text)
+	}
+
+	return strings.Join(texts, " ")
+}
+
+// foreignKeyDefinition returns the definition of a foreign key without its constraint name,
+// so that a named and an unnamed foreign key are the same.
+func foreignKeyDefinition(fk dialect.ForeignKey) string {
+	return fmt.Sprintf("(%s) %s (%s) %s %s",
+		strings.Join(fk.ForeignColumns(), ","),
+		fk.ReferenceTableName(),
+		strings.Join(fk.ReferenceColumns(), ","),
+		fk.DeleteOption(),
+		fk.UpdateOption())
+}
+
+func (td tableDiff) Name() string {
+	return td.name
+}
+
+func (td tableDiff) AddColumns() []dialect.Column {
+	return td.addColumns
+}
+
+func (td tableDiff) ModifyColumns() []dialect.Column {
+	return td.modifyColumns
+}
+
+func (td tableDiff) DropColumns() []dialect.Column {
+	return td.dropColumns
+}
+
+func (td tableDiff) AddIndexes() dialect.Indexes {
+	return td.addIndexes
+}
+
+func (td tableDiff) DropIndexes() dialect.Indexes {
+	return td.dropIndexes
+}
+
+func (td tableDiff) AddForeignKeys() dialect.ForeignKeys {
+	return td.addForeignKeys
+}
+
+func (td tableDiff) DropForeignKeys() dialect.ForeignKeys {
+	return td.dropForeignKeys
+}
+
+func (td tableDiff) AddPrimaryKey() dialect.PrimaryKey {
+	return td.addPrimaryKey
+}
+
+func (td tableDiff) DropPrimaryKey() dialect.PrimaryKey {
+	return td.dropPrimaryKey
+}
+
+func (td tableDiff) AddChecks() dialect.Checks {
+	return td.addChecks
+}
+
+func (td tableDiff) DropChecks() dialect.Checks {
+	return td.dropChecks
+}
+
+func (td tableDiff) ModifyEnumColumns() []dialect.EnumColumn {
+	return td.modifyEnums
+}
+
+func (td tableDiff) Dialect() dialect.Dialect {
+	return td.dialect
+}
+
+func (td tableDiff) empty() bool {
+	return len(td.addColumns) == 0 && len(td.modifyColumns) == 0 && len(td.dropColumns) == 0 &&
+		len(td.addIndexes) == 0 && len(td.dropIndexes) == 0 &&
+		len(td.addForeignKeys) == 0 && len(td.dropForeignKeys) == 0 &&
+		td.addPrimaryKey == nil && td.dropPrimaryKey == nil &&
+		len(td.addChecks) == 0 && len(td.dropChecks) == 0 &&
+		len(td.modifyEnums) == 0
+}
+
+// GenerateDiff generate ddl file which migrates the tables of prev to the tables of dm
+func (dm *DDLMaker) GenerateDiff(prev *DDLMaker) error {
+	log.Printf("start generate diff %s \n", dm.config.OutFilePath)
+
+	var ddl bytes.Buffer
+	err := dm.GenerateDiffTo(&ddl, prev)
+	if err != nil {
+		return err
+	}
+	if err := writeDDLFile(dm.config.OutFilePath, ddl.Bytes()); err != nil {
+		return err
+	}
+
+	log.Printf("done generate diff %s \n", dm.config.OutFilePath)
+
+	return nil
+}
+
+// GenerateDiffTo writes ddl which migrates the tables of prev to the tables of dm to w
+func (dm *DDLMaker) GenerateDiffTo(w io.Writer, prev *DDLMaker) error {
+	if err := dm.parse(); err != nil {
+		return errors.Wrap(err, "error parse")
+	}
+	if err := prev.parse(); err != nil {
+		return errors.Wrap(err, "error parse previous")
+	}
+
+	err := dm.generateDiff(w, prev.Tables)
+	if err != nil {
+		return errors.Wrap(err, "error generate diff")
+	}
+
+	return nil
+}
+
+func (dm *DDLMaker) generateDiff(w io.Writer, prevTables []dialect.Table) error {
+	migrator, ok := dm.Dialect.(dialect.Migrator)
+	if !ok {
+		return fmt.Errorf("%T does not support diff", dm.Dialect)
+	}
+
+	header, err := template.New("header").Parse(dm.Dialect.HeaderTemplate())
+	if err != nil {
+		return errors.Wrap(err, "error parse header template")
+	}
+
+	footer, err := template.New("footer").Parse(dm.Dialect.FooterTemplate())
+	if err != nil {
+		return errors.Wrap(err, "error parse header footer")
+	}
+
+	tmpl, err := template.New("ddl").Parse(dm.Dialect.TableTemplate())
+	if err != nil {
+		return errors.Wrap(err, "error parse template")
+	}
+
+	alter, err := template.New("alter").Parse(migrator.AlterTableTemplate())
+	if err != nil {
+		return errors.Wrap(err, "error parse alter template")
+	}
+
+	drop, err := template.New("drop").Parse(migrator.DropTableTemplate())
+	if err != nil {
+		return errors.Wrap(err, "error parse drop template")
+	}
+
+	prevTableMap := make(map[string]dialect.Table, len(prevTables))
+	for _, table := range prevTables {
+		prevTableMap[table.Name()] = table
+	}
+	currentTableMap := make(map[string]dialect.Table, len(dm.Tables))
+	for _, table := range dm.Tables {
+		currentTableMap[table.Name()] = table
+	}
+
+	// the foreign keys in a cycle are deferred only for the created tables
+	tables, cyclic := sortTables(dm.Tables, deferCycles(dm.Dialect))
+	var deferred []tableDiff
+	for _, diff := range cyclic {
+		if _, ok := prevTableMap[diff.Name()]; !ok {
+			deferred = append(deferred, diff)
+		}
+	}
+
+	if err := header.Execute(w, nil); err != nil {
+		return errors.Wrap(err, "template execute error")
+	}
+	for _, table := range tables {
+		prev, ok := prevTableMap[table.Name()]
+		if !ok {
+			if err := tmpl.Execute(w, table); err != nil {
+				return errors.Wrap(err, "template execute error")
+			}
+			continue
+		}
+
+		diff, err := newTableDiff(prev, currentTableMap[table.Name()])
+		if err != nil {
+			return errors.Wrapf(err, "error diff table %s", table.Name())
+		}
+		if diff.empty() {
+			continue
+		}
+		if err := alter.Execute(w, diff); err != nil {
+			return errors.Wrap(err, "template execute error")
+		}
+	}
+	for _, diff := range deferred {
+		if err := alter.Execute(w, diff); err != nil {
+			return errors.Wrap(err, "template execute error")
+		}
+	}
+	for _, table := range prevTables {
+		if _, ok := currentTableMap[table.Name()]; ok {
+			continue
+		}
+		if err := drop.Execute(w, table); err != nil {
+			return errors.Wrap(err, "template execute error")
+		}
+	}
+	if err := footer.Execute(w, nil); err != nil {
+		return errors.Wrap(err, "template execute error")
+	}
+
+	return nil
+}
@@ -0,0 +1,542 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
+)
+
+type DiffUserV1 struct {
+	ID        uint64
+	Name      string
+	Nickname  string
+	CreatedAt time.Time
+}
+
+func (u DiffUserV1) Table() string {
+	return "diff_user"
+}
+
+func (u DiffUserV1) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (u DiffUserV1) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		mysql.AddIndex("name_idx", "name"),
+		mysql.AddIndex("nickname_idx", "nickname"),
+	}
+}
+
+func (u DiffUserV1) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"id"}, []string{"id"}, "account", mysql.WithForeignKeyName("diff_user_account_fk")),
+	}
+}
+
+type DiffUserV2 struct {
+	ID        uint64
+	Name      string         `ddl:"size=100"`
+	Email     sql.NullString `ddl:"null"`
+	CreatedAt time.Time
+}
+
+func (u DiffUserV2) Table() string {
+	return "diff_user"
+}
+
+func (u DiffUserV2) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (u DiffUserV2) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		mysql.AddUniqueIndex("name_idx", "name"),
+		mysql.AddIndex("email_idx", "email"),
+	}
+}
+
+func (u DiffUserV2) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"email"}, []string{"email"}, "account"),
+	}
+}
+
+func TestGenerateDiff(t *testing.T) {
+	m := mysql.MySQL{}
+	generatedDDL := m.HeaderTemplate() + "\n" +
+		"ALTER TABLE `diff_user` DROP FOREIGN KEY `diff_user_account_fk`;\n" +
+		"ALTER TABLE `diff_user` DROP INDEX `name_idx`;\n" +
+		"ALTER TABLE `diff_user` DROP INDEX `nickname_idx`;\n" +
+		"ALTER TABLE `diff_user` DROP COLUMN `nickname`;\n" +
+		"ALTER TABLE `diff_user` ADD COLUMN `email` VARCHAR(191) NULL;\n" +
+		"ALTER TABLE `diff_user` MODIFY COLUMN `name` VARCHAR(100) NOT NULL;\n" +
+		"ALTER TABLE `diff_user` ADD UNIQUE `name_idx` (`name`);\n" +
+		"ALTER TABLE `diff_user` ADD INDEX `email_idx` (`email`);\n" +
+		"ALTER TABLE `diff_user` ADD FOREIGN KEY (`email`) REFERENCES `account` (`email`);\n" +
+		"\n" +
+		"\nDROP TABLE IF EXISTS `test2`;\n\n" +
+		m.FooterTemplate()
+
+	conf := Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	}
+
+	prev, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = prev.AddStruct(DiffUserV1{}, Test2{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = prev.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	dm, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffUserV2{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generateDiff(&ddl, prev.Tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
+
+type DiffLogV1 struct {
+	ID        uint64
+	Price     uint64
+	CreatedAt time.Time
+}
+
+func (l DiffLogV1) Table() string {
+	return "diff_log"
+}
+
+func (l DiffLogV1) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (l DiffLogV1) Checks() dialect.Checks {
+	return dialect.Checks{
+		mysql.AddCheck("price_positive", "price >= 0"),
+	}
+}
+
+type DiffLogV2 struct {
+	ID        uint64
+	Price     uint64
+	CreatedAt time.Time
+}
+
+func (l DiffLogV2) Table() string {
+	return "diff_log"
+}
+
+func (l DiffLogV2) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id", "created_at")
+}
+
+func (l DiffLogV2) Checks() dialect.Checks {
+	return dialect.Checks{
+		mysql.AddCheck("price_limit", "price < 100000"),
+	}
+}
+
+func (l DiffLogV2) Partitioning() dialect.Partitioning {
+	return mysql.PartitionByRange("YEAR(created_at)",
+		mysql.AddPartition("p2020", "2021"),
+		mysql.AddPartition("pmax", "MAXVALUE"),
+	)
+}
+
+type DiffLogV3 struct {
+	DiffLogV2
+}
+
+func (l DiffLogV3) TableOptions() dialect.TableOptions {
+	return dialect.TableOptions{
+		mysql.TableOptionRowFormat: "COMPRESSED",
+	}
+}
+
+type DiffEvent struct {
+	ID        uint64
+	CreatedAt time.Time
+}
+
+func (e DiffEvent) Table() string {
+	return "diff_event"
+}
+
+func (e DiffEvent) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id", "created_at")
+}
+
+func (e DiffEvent) Partitioning() dialect.Partitioning {
+	return mysql.PartitionByRangeColumns([]string{"created_at"},
+		mysql.AddPartition("p2020", "'2021-01-01'"),
+		mysql.AddPartition("pmax", "MAXVALUE"),
+	)
+}
+
+func TestGenerateDiffRangeColumns(t *testing.T) {
+	m := mysql.MySQL{}
+	generatedDDL := m.HeaderTemplate() + m.FooterTemplate()
+
+	dm, err := New(Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffEvent{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	tables, err := dm.ParseDDL(strings.NewReader("CREATE TABLE `diff_event` (\n" +
+		"  `id` bigint unsigned NOT NULL,\n" +
+		"  `created_at` datetime NOT NULL,\n" +
+		"  PRIMARY KEY (`id`,`created_at`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci\n" +
+		"/*!50500 PARTITION BY RANGE  COLUMNS(created_at)\n" +
+		"(PARTITION p2020 VALUES LESS THAN ('2021-01-01') ENGINE = InnoDB,\n" +
+		" PARTITION pmax VALUES LESS THAN (MAXVALUE) ENGINE = InnoDB) */;\n"))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generateDiff(&ddl, tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
+
+type DiffItem struct {
+	ID    uint64
+	Price uint64
+	Total uint64 `ddl:"generated=price * 2"`
+	Valid bool   `ddl:"generated=price IS NOT NULL,stored"`
+}
+
+func (i DiffItem) Table() string {
+	return "diff_item"
+}
+
+func (i DiffItem) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func TestGenerateDiffGenerated(t *testing.T) {
+	m := mysql.MySQL{}
+	generatedDDL := m.HeaderTemplate() + m.FooterTemplate()
+
+	dm, err := New(Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffItem{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	// mysqldump writes the generated expressions with backquotes and parentheses
+	tables, err := dm.ParseDDL(strings.NewReader("CREATE TABLE `diff_item` (\n" +
+		"  `id` bigint unsigned NOT NULL,\n" +
+		"  `price` bigint unsigned NOT NULL,\n" +
+		"  `total` bigint unsigned GENERATED ALWAYS AS ((`price` * 2)) VIRTUAL NOT NULL,\n" +
+		"  `valid` tinyint(1) GENERATED ALWAYS AS ((`price` is not null)) STORED NOT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n"))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generateDiff(&ddl, tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
+
+type DiffPrice struct {
+	ID    uint64
+	Name  string  `ddl:"size=64"`
+	Token string  `ddl:"size=64,collate=utf8mb4_bin"`
+	Price float64 `ddl:"type=decimal,precision=12,scale=2,default=0"`
+	Rate  float64 `ddl:"type=decimal,precision=4,scale=3,default=+0.50"`
+}
+
+func (p DiffPrice) Table() string {
+	return "diff_price"
+}
+
+func (p DiffPrice) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func TestGenerateDiffNormalized(t *testing.T) {
+	m := mysql.MySQL{}
+	generatedDDL := m.HeaderTemplate() + m.FooterTemplate()
+
+	dm, err := New(Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffPrice{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	// mysqldump writes the numeric defaults as strings, and the character sets and the collations of the table
+	tables, err := dm.ParseDDL(strings.NewReader("CREATE TABLE `diff_price` (\n" +
+		"  `id` bigint unsigned NOT NULL,\n" +
+		"  `name` varchar(64) COLLATE utf8mb4_0900_ai_ci NOT NULL,\n" +
+		"  `token` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,\n" +
+		"  `price` decimal(12,2) NOT NULL DEFAULT '0.00',\n" +
+		"  `rate` decimal(4,3) NOT NULL DEFAULT '0.500',\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n"))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.generateDiff(&ddl, tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	testcases := map[string]string{
+		"0.00":   "0",
+		"-0.0":   "0",
+		"+00.50": "0.5",
+		"-1.250": "-1.25",
+		".5":     "0.5",
+		"100":    "100",
+		"1e3":    "1e3",
+		"'0.00'": "'0.00'",
+		"now()":  "now()",
+	}
+	for value, expected := range testcases {
+		if normalized := normalizeNumber(value); normalized != expected {
+			t.Fatalf("error normalize number %s. result: %s", value, normalized)
+		}
+	}
+}
+
+func TestGenerateDiffConstraints(t *testing.T) {
+	m := mysql.MySQL{}
+	generatedDDL := m.HeaderTemplate() + "\n" +
+		"ALTER TABLE `diff_log` DROP CHECK `price_positive`;\n" +
+		"ALTER TABLE `diff_log` DROP PRIMARY KEY;\n" +
+		"ALTER TABLE `diff_log` ADD PRIMARY KEY (`id`, `created_at`);\n" +
+		"ALTER TABLE `diff_log` ADD CONSTRAINT `price_limit` CHECK (price < 100000);\n" +
+		"\n" +
+		m.FooterTemplate()
+
+	conf := Config{
+		DB: DBConfig{
+			Driver:  "mysql",
+			Engine:  "InnoDB",
+			Charset: "utf8mb4",
+		},
+	}
+
+	prev, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = prev.AddStruct(DiffLogV1{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	dm, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffLogV2{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	var ddl bytes.Buffer
+	err = dm.GenerateDiffTo(&ddl, prev)
+	if err == nil || !strings.Contains(err.Error(), "partitioning is changed") {
+		t.Fatal("error diff partitioning", err)
+	}
+
+	// the check, the table options and the partitioning written by mysqldump are compared with their definitions
+	tables, err := dm.ParseDDL(strings.NewReader("CREATE TABLE `diff_log` (\n" +
+		"  `id` bigint unsigned NOT NULL,\n" +
+		"  `price` bigint unsigned NOT NULL,\n" +
+		"  `created_at` datetime NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  CONSTRAINT `price_positive` CHECK ((`price` >= 0))\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci\n" +
+		"/*!50100 PARTITION BY RANGE (year(`created_at`))\n" +
+		"(PARTITION p2020 VALUES LESS THAN (2021) ENGINE = InnoDB,\n" +
+		" PARTITION pmax VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */;\n"))
+	if err != nil {
+		t.Fatal("error parse ddl", err)
+	}
+	ddl.Reset()
+	err = dm.generateDiff(&ddl, tables)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+
+	dm, err = New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffLogV3{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+	err = dm.generateDiff(&ddl, tables)
+	if err == nil || !strings.Contains(err.Error(), "table options are changed") {
+		t.Fatal("error diff table options", err)
+	}
+}
+
+type DiffTicketV1 struct {
+	ID     uint64
+	Status string `ddl:"type=enum,values=('open'|'closed')"`
+}
+
+func (t DiffTicketV1) Table() string {
+	return "diff_ticket"
+}
+
+func (t DiffTicketV1) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+type DiffTicketV2 struct {
+	ID     uint64
+	Status string `ddl:"type=enum,values=('open'|'pending'|'closed')"`
+}
+
+func (t DiffTicketV2) Table() string {
+	return "diff_ticket"
+}
+
+func (t DiffTicketV2) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func TestGenerateDiffEnum(t *testing.T) {
+	conf := Config{
+		DB: DBConfig{Driver: "postgres"},
+	}
+
+	prev, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = prev.AddStruct(DiffTicketV1{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	dm, err := New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(DiffTicketV2{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	pg := postgres.PostgreSQL{}
+	generatedDDL := pg.HeaderTemplate() + "\n" +
+		`ALTER TABLE "diff_ticket" ALTER COLUMN "status" TYPE TEXT, ALTER COLUMN "status" SET NOT NULL, ALTER COLUMN "status" DROP DEFAULT;` + "\n" +
+		`COMMENT ON COLUMN "diff_ticket"."status" IS NULL;` + "\n" +
+		`ALTER TABLE "diff_ticket" DROP CONSTRAINT IF EXISTS "diff_ticket_status_check", ADD CONSTRAINT "diff_ticket_status_check" CHECK ("status" IN ('open', 'pending', 'closed'));` + "\n" +
+		"\n" +
+		pg.FooterTemplate()
+
+	var ddl bytes.Buffer
+	err = dm.GenerateDiffTo(&ddl, prev)
+	if err != nil {
+		t.Fatal("error generate diff", err)
+	}
+	if ddl.String() != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl.String(), generatedDDL)
+	}
+}
@@ -0,0 +1,79 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/pkg/errors"
+)
+
+// GenerateDocTo writes the tables as a Markdown document to w,
+// which lists the columns with their definitions and comments, and the keys, indexes and checks of each table.
+func (dm *DDLMaker) GenerateDocTo(w io.Writer) error {
+	if err := dm.parse(); err != nil {
+		return errors.Wrap(err, "error parse")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Tables\n")
+	for _, t := range dm.Tables {
+		if err := writeTableDoc(&buf, t); err != nil {
+			return errors.Wrapf(err, "error generate doc of %s", t.Name())
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "error write doc")
+	}
+
+	return nil
+}
+
+func writeTableDoc(buf *bytes.Buffer, t dialect.Table) error {
+	fmt.Fprintf(buf, "\n## %s\n\n", t.Name())
+	if t.Comment() != "" {
+		fmt.Fprintf(buf, "%s\n\n", t.Comment())
+	}
+
+	buf.WriteString("| Column | Definition | Comment |\n")
+	buf.WriteString("| --- | --- | --- |\n")
+	for _, c := range t.Columns() {
+		sql, err := c.ToSQL()
+		if err != nil {
+			return errors.Wrapf(err, "error column %s", c.Name())
+		}
+		sql = strings.TrimPrefix(sql, t.Dialect().Quote(c.Name())+" ")
+		fmt.Fprintf(buf, "| %s | %s | %s |\n", c.Name(), escapeDocCell(strings.TrimSpace(sql)), escapeDocCell(c.Comment()))
+	}
+
+	var keys []string
+	if pk := t.PrimaryKey(); pk != nil {
+		keys = append(keys, pk.ToSQL())
+	}
+	for _, index := range t.Indexes().Sort() {
+		keys = append(keys, index.ToSQL())
+	}
+	for _, fk := range t.ForeignKeys().Sort() {
+		keys = append(keys, fk.ToSQL())
+	}
+	for _, check := range t.Checks().Sort() {
+		keys = append(keys, check.ToSQL())
+	}
+	if len(keys) > 0 {
+		buf.WriteString("\n")
+		for _, key := range keys {
+			fmt.Fprintf(buf, "- `` %s ``\n", key)
+		}
+	}
+
+	return nil
+}
+
+// escapeDocCell escapes s to be written in a cell of a Markdown table
+func escapeDocCell(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
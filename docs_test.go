@@ -0,0 +1,38 @@
+package ddlmaker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDocTo(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(Test4{}, Test5{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dm.GenerateDocTo(&buf); err != nil {
+		t.Fatal("error generate doc", err)
+	}
+	doc := buf.String()
+	for _, expected := range []string{
+		"# Tables\n",
+		"\n## `test4`\n\n" + (Test4{}).Comment() + "\n\n| Column | Definition | Comment |\n",
+		"\n## `test5`\n\n| Column | Definition | Comment |\n",
+		"| memo | VARCHAR(191) NULL COMMENT 'user''s memo, free text' | user's memo, free text |\n",
+		"- `` PRIMARY KEY (`id`) ``\n",
+		"- `` CONSTRAINT `stock_positive` CHECK (stock >= 0) ``\n",
+	} {
+		if !strings.Contains(doc, expected) {
+			t.Errorf("error doc does not contain %q\n%s", expected, doc)
+		}
+	}
+}
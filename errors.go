@@ -0,0 +1,18 @@
+package ddlmaker
+
+import (
+	"strings"
+)
+
+// Errors is a list of errors which are reported at once
+type Errors []error
+
+// Error returns the messages of the errors joined by newlines
+func (es Errors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, err := range es {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
@@ -1,12 +1,15 @@
 package ddlmaker
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	"log"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/kayac/ddl-maker/dialect"
+	"github.com/pkg/errors"
 	"github.com/serenize/snaker"
 )
 
@@ -15,6 +18,11 @@ type Table interface {
 	Table() string
 }
 
+// Comment is for type assertion
+type Comment interface {
+	Comment() string
+}
+
 // PrimaryKey is for type assertion
 type PrimaryKey interface {
 	PrimaryKey() dialect.PrimaryKey
@@ -30,48 +38,253 @@ type Index interface {
 	Indexes() dialect.Indexes
 }
 
-func (dm *DDLMaker) parse() {
+// Check is for type assertion
+type Check interface {
+	Checks() dialect.Checks
+}
+
+// TableOptions is for type assertion
+type TableOptions interface {
+	TableOptions() dialect.TableOptions
+}
+
+// Partitioning is for type assertion
+type Partitioning interface {
+	Partitioning() dialect.Partitioning
+}
+
+// DDLType is for type assertion of a field type which declares its sql type
+type DDLType interface {
+	DDLType() string
+}
+
+// EnumValues is for type assertion of a field type which has a fixed set of values
+type EnumValues interface {
+	EnumValues() []string
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+func (dm *DDLMaker) parse() error {
+	var errs Errors
+
+	dm.Tables = nil
 	for _, s := range dm.Structs {
 		val := reflect.Indirect(reflect.ValueOf(s))
 		rt := val.Type()
 
-		var columns []dialect.Column
-		for i := 0; i < rt.NumField(); i++ {
-			rtField := rt.Field(i)
-			column, err := parseField(rtField, dm.Dialect)
-			if err != nil {
-				if err == ErrIgnoreField {
-					continue
-				}
-				log.Fatalln("error parse field", err.Error())
+		columns, fieldErrs := parseFields(rt, rt.Name(), "", dm.Dialect, nil)
+		errs = append(errs, fieldErrs...)
+
+		dm.Tables = append(dm.Tables, parseTable(s, columns, dm.Dialect))
+	}
+	dm.Tables = append(dm.Tables, dm.addedTables...)
+	tableNames := make(map[string]bool, len(dm.Tables))
+	for _, t := range dm.Tables {
+		// tables are sorted and diffed by their names
+		if tableNames[t.Name()] {
+			errs = append(errs, fmt.Errorf("error parse table %s: table name is duplicated", t.Name()))
+		}
+		tableNames[t.Name()] = true
+		if err := checkPartitioning(t, dm.Dialect); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse table %s", t.Name()))
+		}
+	}
+
+	tables, err := overrideTables(dm.Tables, dm.config.Tables)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	dm.Tables = tables
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// checkPartitioning returns an error if t is partitioned by the dialect which does not support partitioning
+func checkPartitioning(t dialect.Table, d dialect.Dialect) error {
+	if t.Partitioning() == nil {
+		return nil
+	}
+	if p, ok := d.(dialect.Partitioner); !ok || !p.SupportPartitioning() {
+		return fmt.Errorf("%T does not support partitioning", d)
+	}
+
+	return nil
+}
+
+// overrideTables applies configs to the tables of the same names, and returns an error for the configs of unknown tables
+func overrideTables(tables []dialect.Table, configs map[string]TableConfig) ([]dialect.Table, error) {
+	if len(configs) == 0 {
+		return tables, nil
+	}
+
+	found := make(map[string]bool, len(configs))
+	overridden := make([]dialect.Table, 0, len(tables))
+	for _, t := range tables {
+		tt, ok := t.(table)
+		if !ok {
+			overridden = append(overridden, t)
+			continue
+		}
+		conf, ok := configs[tt.name]
+		if !ok {
+			overridden = append(overridden, t)
+			continue
+		}
+		found[tt.name] = true
+		if conf.Skip {
+			continue
+		}
+
+		if conf.Comment != "" {
+			tt.comment = conf.Comment
+		}
+		if len(conf.Options) > 0 {
+			options := make(dialect.TableOptions, len(tt.options)+len(conf.Options))
+			for k, v := range tt.options {
+				options[k] = v
 			}
-			columns = append(columns, column)
+			for k, v := range conf.Options {
+				options[k] = v
+			}
+			tt.options = options
 		}
+		overridden = append(overridden, tt)
+	}
 
-		table := parseTable(s, columns, dm.Dialect)
-		dm.Tables = append(dm.Tables, table)
+	var unknown []string
+	for name := range configs {
+		if !found[name] {
+			unknown = append(unknown, name)
+		}
 	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return overridden, fmt.Errorf("error override table %s: table is not found", strings.Join(unknown, ", "))
+	}
+
+	return overridden, nil
 }
 
-func parseField(field reflect.StructField, d dialect.Dialect) (dialect.Column, error) {
-	tagStr := strings.Replace(field.Tag.Get(TAGPREFIX), " ", "", -1)
+// parseFields parses the fields of rt into columns.
+// Embedded structs and struct fields with prefix tag are flattened recursively, and their column names are prefixed.
+func parseFields(rt reflect.Type, path, prefix string, d dialect.Dialect, parents []reflect.Type) ([]dialect.Column, Errors) {
+	var errs Errors
+	var columns []dialect.Column
 
-	for _, tag := range strings.Split(tagStr, ",") {
-		if tag == IGNORETAG {
-			return nil, ErrIgnoreField
+	for _, parent := range parents {
+		if parent == rt {
+			return nil, Errors{fmt.Errorf("error parse field %s: %s is embedded recursively", path, rt)}
 		}
 	}
+	parents = append(parents, rt)
+
+	for i := 0; i < rt.NumField(); i++ {
+		rtField := rt.Field(i)
+		fieldPath := fmt.Sprintf("%s.%s", path, rtField.Name)
+		// the unexported fields of a flattened struct, such as the fields of a struct in another package, are not columns
+		if len(parents) > 1 && rtField.PkgPath != "" && !rtField.Anonymous {
+			continue
+		}
+
+		st, fieldPrefix, err := flattenField(rtField, d)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		if st != nil {
+			cs, fieldErrs := parseFields(st, fieldPath, prefix+fieldPrefix, d, parents)
+			errs = append(errs, fieldErrs...)
+			columns = append(columns, cs...)
+			continue
+		}
+
+		column, err := parseField(rtField, prefix, d)
+		if err != nil {
+			if err == ErrIgnoreField {
+				continue
+			}
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		if _, err := column.ToSQL(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, errs
+}
+
+// flattenField returns the struct type and the column name prefix when field is an embedded struct or has prefix tag.
+// The struct type is nil if field is not flattened.
+func flattenField(field reflect.StructField, d dialect.Dialect) (reflect.Type, string, error) {
+	ft := field.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+
+	isStruct := ft.Kind() == reflect.Struct && !isColumnType(ft, d)
+	prefix, ok, err := flattenTag(fieldTag(field), field.Anonymous, isStruct)
+	if err != nil || !ok {
+		return nil, "", err
+	}
+
+	return ft, prefix, nil
+}
+
+// flattenTag returns the column name prefix and true when the field of the tag is an embedded struct or has prefix tag
+func flattenTag(tag string, anonymous, isStruct bool) (string, bool, error) {
+	specs, err := parseTag(tag)
+	if err != nil {
+		return "", false, errors.Wrap(err, "error parse tag")
+	}
+	if _, ok := specs[IGNORETAG]; ok {
+		return "", false, nil
+	}
+
+	prefix, hasPrefix := specs["prefix"]
+	if !anonymous && !hasPrefix {
+		return "", false, nil
+	}
+	if !isStruct {
+		if hasPrefix {
+			return "", false, fmt.Errorf("prefix is only for struct field")
+		}
+		return "", false, nil
+	}
+
+	return prefix, true, nil
+}
+
+func fieldTag(field reflect.StructField) string {
+	return strings.TrimSpace(field.Tag.Get(TAGPREFIX))
+}
+
+func parseField(field reflect.StructField, prefix string, d dialect.Dialect) (dialect.Column, error) {
+	tagStr := fieldTag(field)
+
+	specs, err := parseTag(tagStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parse tag")
+	}
+	if _, ok := specs[IGNORETAG]; ok {
+		return nil, ErrIgnoreField
+	}
 
 	var typeName string
 	switch {
 	case field.Type.PkgPath() != "":
 		// ex) time.Time
-		pkgName := field.Type.PkgPath()
-		if strings.Contains(pkgName, "/") {
-			pkgs := strings.Split(pkgName, "/")
-			pkgName = pkgs[len(pkgs)-1]
-		}
-		typeName = fmt.Sprintf("%s.%s", pkgName, field.Type.Name())
+		typeName = field.Type.String()
 	case field.Type.Kind() == reflect.Ptr:
 		// pointer type
 		typeName = fmt.Sprintf("*%s", field.Type.Elem())
@@ -82,14 +295,80 @@ func parseField(field reflect.StructField, d dialect.Dialect) (dialect.Column, e
 		typeName = field.Type.Name()
 	}
 
-	return newColumn(snaker.CamelToSnake(field.Name), typeName, tagStr, d), nil
+	column := newColumn(prefix+snaker.CamelToSnake(field.Name), typeName, tagStr, d)
+
+	elemType := field.Type
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	v := reflect.New(elemType).Interface()
+	enum, isEnum := v.(EnumValues)
+	if isEnum {
+		column.values = enum.EnumValues()
+	}
+	if ddlType, ok := v.(DDLType); ok {
+		column.sqlType = ddlType.DDLType()
+	} else if _, err := d.ToSQL(typeName, 0); err != nil && (isEnum || isValuer(elemType)) {
+		// a type implementing driver.Valuer, sql.Scanner or EnumValues is stored as its underlying kind
+		if kindName, ok := kindTypeName(elemType); ok {
+			if field.Type.Kind() == reflect.Ptr {
+				kindName = "*" + kindName
+			}
+			column.typeName = kindName
+		}
+	}
+
+	return column, nil
+}
+
+// isColumnType reports whether the struct type t is a single column, such as time.Time and sql.NullString,
+// which is mapped to a type by the dialect, implements driver.Valuer or sql.Scanner, or declares DDLType
+func isColumnType(t reflect.Type, d dialect.Dialect) bool {
+	if _, err := d.ToSQL(t.String(), 0); err == nil {
+		return true
+	}
+	if _, ok := reflect.New(t).Interface().(DDLType); ok {
+		return true
+	}
+
+	return isValuer(t)
+}
+
+// isValuer reports whether t implements driver.Valuer or sql.Scanner
+func isValuer(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return t.Implements(valuerType) || ptr.Implements(valuerType) || ptr.Implements(scannerType)
+}
+
+// kindTypeName returns the type name of the underlying kind of t
+func kindTypeName(t reflect.Type) (string, bool) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t.Kind().String(), true
+	case reflect.Int:
+		return "int64", true
+	case reflect.Uint:
+		return "uint64", true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "[]uint8", true
+		}
+	}
+
+	return "", false
 }
 
 func parseTable(s interface{}, columns []dialect.Column, d dialect.Dialect) dialect.Table {
 	var tableName string
+	var comment string
 	var primaryKey dialect.PrimaryKey
 	var foreignKeys dialect.ForeignKeys
 	var indexes dialect.Indexes
+	var checks dialect.Checks
+	var options dialect.TableOptions
+	var partitioning dialect.Partitioning
 
 	if v, ok := s.(Table); ok {
 		tableName = snaker.CamelToSnake(v.Table())
@@ -97,6 +376,9 @@ func parseTable(s interface{}, columns []dialect.Column, d dialect.Dialect) dial
 		val := reflect.Indirect(reflect.ValueOf(s))
 		tableName = snaker.CamelToSnake(val.Type().Name())
 	}
+	if v, ok := s.(Comment); ok {
+		comment = v.Comment()
+	}
 	if v, ok := s.(PrimaryKey); ok {
 		primaryKey = v.PrimaryKey()
 	}
@@ -106,6 +388,15 @@ func parseTable(s interface{}, columns []dialect.Column, d dialect.Dialect) dial
 	if v, ok := s.(Index); ok {
 		indexes = v.Indexes()
 	}
+	if v, ok := s.(Check); ok {
+		checks = v.Checks()
+	}
+	if v, ok := s.(TableOptions); ok {
+		options = v.TableOptions()
+	}
+	if v, ok := s.(Partitioning); ok {
+		partitioning = v.Partitioning()
+	}
 
-	return newTable(tableName, primaryKey, foreignKeys, columns, indexes, d)
+	return newTable(tableName, comment, primaryKey, foreignKeys, columns, indexes, checks, options, partitioning, d)
 }
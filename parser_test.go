@@ -2,7 +2,9 @@ package ddlmaker
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -79,7 +81,7 @@ func TestParseField(t *testing.T) {
 	}
 
 	for i := 0; i < rt.NumField(); i++ {
-		column, err := parseField(rt.Field(i), mysql.MySQL{})
+		column, err := parseField(rt.Field(i), "", mysql.MySQL{})
 		if err != nil {
 			if err == ErrIgnoreField {
 				continue
@@ -115,3 +117,311 @@ func TestParseTable(t *testing.T) {
 		t.Fatal("error parse fk: ", len(table.ForeignKeys()))
 	}
 }
+
+type status int
+
+type T2 struct {
+	ID     uint64
+	Status status
+	Name   string `ddl:"size=abc"`
+}
+
+func TestParseErrors(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T2{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.parse()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("error parse errors: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "T2.Status") || !strings.Contains(errs[1].Error(), "T2.Name") {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+}
+
+func TestParseDuplicatedTable(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T1{}, Test1{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.parse()
+	if err == nil || !strings.Contains(err.Error(), "error parse table `test1`: table name is duplicated") {
+		t.Fatalf("error parse duplicated table: %v", err)
+	}
+}
+
+type Timestamps struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Address struct {
+	City    string
+	ZipCode string `ddl:"size=8"`
+}
+
+type T3 struct {
+	ID uint64
+	*Timestamps
+	Home    Address  `ddl:"prefix=home_"`
+	Office  *Address `ddl:"prefix=office_"`
+	Ignored Address  `ddl:"-"`
+}
+
+type T4 struct {
+	ID     uint64
+	Name   string `ddl:"prefix=name_"`
+	Status status
+}
+
+func TestParseEmbeddedFields(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T3{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var names []string
+	for _, c := range dm.Tables[0].Columns() {
+		names = append(names, c.Name())
+	}
+	expected := []string{"id", "created_at", "updated_at", "home_city", "home_zip_code", "office_city", "office_zip_code"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("error embedded columns: %v", names)
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T4{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, ok := dm.parse().(Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "T4.Name") || !strings.Contains(errs[1].Error(), "T4.Status") {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+}
+
+type Memo struct {
+	Memo  string `ddl:"null"`
+	draft bool
+}
+
+type T9 struct {
+	ID uint64
+	time.Time
+	sql.NullString
+	*Memo
+}
+
+func TestParseEmbeddedColumnTypes(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T9{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	var names []string
+	for _, c := range dm.Tables[0].Columns() {
+		names = append(names, c.Name())
+	}
+	expected := []string{"id", "time", "null_string", "memo"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("error embedded columns: %v", names)
+	}
+}
+
+type T6 struct {
+	ID     uint64
+	Status status
+	Prev   *status `ddl:"null"`
+}
+
+func TestParseRegisteredType(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.RegisterType(status(0), func(size uint64) string {
+		return "TINYINT unsigned"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T6{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+	expected := []string{
+		"`id` BIGINT unsigned NOT NULL",
+		"`status` TINYINT unsigned NOT NULL",
+		"`prev` TINYINT unsigned NULL",
+	}
+	for i, c := range dm.Tables[0].Columns() {
+		if sql, _ := c.ToSQL(); sql != expected[i] {
+			t.Fatalf("error registered type column: %s", sql)
+		}
+	}
+}
+
+type valuerStatus string
+
+func (s valuerStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+type scannerLevel int
+
+func (l *scannerLevel) Scan(src interface{}) error {
+	return nil
+}
+
+type uuidType [16]byte
+
+func (u uuidType) DDLType() string {
+	return "BINARY(16)"
+}
+
+type T7 struct {
+	ID     uuidType
+	Status valuerStatus  `ddl:"size=16"`
+	Level  *scannerLevel `ddl:"null"`
+	Note   valuerStatus  `ddl:"type=text"`
+}
+
+func TestParseValuerField(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T7{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+	expected := []string{
+		"`id` BINARY(16) NOT NULL",
+		"`status` VARCHAR(16) NOT NULL",
+		"`level` BIGINT NULL",
+		"`note` TEXT NOT NULL",
+	}
+	for i, c := range dm.Tables[0].Columns() {
+		if sql, _ := c.ToSQL(); sql != expected[i] {
+			t.Fatalf("error valuer column: %s", sql)
+		}
+	}
+}
+
+type entryStatus string
+
+func (s entryStatus) EnumValues() []string {
+	return []string{"draft", "published"}
+}
+
+type T8 struct {
+	ID     uint64
+	Status entryStatus
+	Tags   string `ddl:"type=set,values=('go'|'sql')"`
+	Kind   string `ddl:"type=enum,values=(a,b)"`
+}
+
+func TestParseEnumField(t *testing.T) {
+	expected := map[string][]string{
+		"mysql": {
+			"`id` BIGINT unsigned NOT NULL",
+			"`status` ENUM('draft','published') NOT NULL",
+			"`tags` SET('go','sql') NOT NULL",
+			"`kind` ENUM('a','b') NOT NULL",
+		},
+		"postgres": {
+			`"id" BIGINT NOT NULL`,
+			`"status" TEXT CHECK ("status" IN ('draft', 'published')) NOT NULL`,
+		},
+	}
+
+	for driver, columns := range expected {
+		dm, err := New(Config{
+			DB: DBConfig{Driver: driver},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = dm.AddStruct(T8{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = dm.parse()
+		if driver == "postgres" {
+			// SET is not supported by PostgreSQL
+			errs, ok := err.(Errors)
+			if !ok || len(errs) != 1 || !strings.Contains(errs[0].Error(), "T8.Tags") {
+				t.Fatalf("error parse errors: %v", err)
+			}
+		} else if err != nil {
+			t.Fatal("error parse", err)
+		}
+		for i, c := range columns {
+			if sql, _ := dm.Tables[0].Columns()[i].ToSQL(); sql != c {
+				t.Fatalf("error %s enum column: %s", driver, sql)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package ddlmaker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DEFAULTGROUP is the group of the structs registered by Register
+	DEFAULTGROUP = "default"
+)
+
+var (
+	registryMu sync.Mutex
+	// registry is the registered structs keyed by the group, in the order of the registration
+	registry = make(map[string][]interface{})
+)
+
+// Register registers the structs to DEFAULTGROUP, which are added by AddRegistered.
+// It is intended to be called from init() of the packages which declare the structs,
+// and panics if a struct is nil or is already registered to the group.
+func Register(structs ...interface{}) {
+	RegisterGroup(DEFAULTGROUP, structs...)
+}
+
+// RegisterGroup registers the structs to the group, such as the name of a schema.
+// A struct can be registered to several groups.
+func RegisterGroup(group string, structs ...interface{}) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registered := make(map[string]bool, len(registry[group]))
+	for _, s := range registry[group] {
+		registered[fullStructName(s)] = true
+	}
+
+	for _, s := range structs {
+		if s == nil {
+			panic("ddlmaker: Register nil struct")
+		}
+		structName := fullStructName(s)
+		if registered[structName] {
+			panic(fmt.Sprintf("ddlmaker: Register called twice for %s in group %s", structName, group))
+		}
+		registered[structName] = true
+		registry[group] = append(registry[group], s)
+	}
+}
+
+// AddRegistered adds the structs registered to the groups, which is DEFAULTGROUP if no group is given, by AddStruct.
+// It returns an error if a group is not registered, or a struct is registered to several of the groups or is already added.
+func (dm *DDLMaker) AddRegistered(groups ...string) error {
+	if len(groups) == 0 {
+		groups = []string{DEFAULTGROUP}
+	}
+
+	registryMu.Lock()
+	var structs []interface{}
+	var err error
+	for _, group := range groups {
+		ss, ok := registry[group]
+		if !ok {
+			err = fmt.Errorf("group %s is not registered", group)
+			break
+		}
+		structs = append(structs, ss...)
+	}
+	registryMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	added := make(map[string]bool, len(dm.Structs))
+	for _, s := range dm.Structs {
+		added[fullStructName(s)] = true
+	}
+	for _, s := range structs {
+		if name := fullStructName(s); added[name] {
+			return fmt.Errorf("error add registered structs: %s is already added", name)
+		}
+	}
+
+	if err := dm.AddStruct(structs...); err != nil {
+		return errors.Wrap(err, "error add registered structs")
+	}
+
+	return nil
+}
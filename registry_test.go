@@ -0,0 +1,105 @@
+package ddlmaker
+
+import (
+	"strings"
+	"testing"
+)
+
+// registerTestGroup registers the structs to the group, which is removed from the registry when the test finishes
+func registerTestGroup(t *testing.T, group string, structs ...interface{}) {
+	t.Helper()
+	RegisterGroup(group, structs...)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registry, group)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerTestGroup(t, "test_register", Test1{})
+	registerTestGroup(t, "test_register_a", &Test3{}, Test4{})
+	registerTestGroup(t, "test_register_b", Test4{})
+	registerTestGroup(t, "test_register_b", Test5{})
+
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	if err := dm.AddRegistered("test_register", "test_register_a"); err != nil {
+		t.Fatal("error add registered groups", err)
+	}
+	if len(dm.Structs) != 3 {
+		t.Fatal("error add registered structs", dm.Structs)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	for _, table := range []string{"`test1`", "`test3`", "`test4`"} {
+		if !strings.Contains(ddl, "CREATE TABLE "+table) {
+			t.Errorf("error generate registered table %s: %s", table, ddl)
+		}
+	}
+
+	if err := dm.AddRegistered("test_register_b"); err == nil || !strings.Contains(err.Error(), "Test4 is already added") {
+		t.Fatal("error add a struct which is already added", err)
+	}
+	if len(dm.Structs) != 3 {
+		t.Fatal("error add registered structs partially", dm.Structs)
+	}
+	if err := dm.AddRegistered("unknown"); err == nil {
+		t.Fatal("error add unknown group")
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	if err := dm.AddRegistered("test_register_a", "test_register_b"); err == nil || !strings.Contains(err.Error(), "Test4 is already added") {
+		t.Fatal("error add a struct registered to several groups", err)
+	}
+}
+
+func TestAddRegisteredAddedStruct(t *testing.T) {
+	registerTestGroup(t, "test_register_added", &Test1{})
+
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	if err := dm.AddStruct(Test1{}); err != nil {
+		t.Fatal("error add struct", err)
+	}
+	if err := dm.AddRegistered("test_register_added"); err == nil || !strings.Contains(err.Error(), "Test1 is already added") {
+		t.Fatal("error add a registered struct which is added by AddStruct", err)
+	}
+	if len(dm.Structs) != 1 {
+		t.Fatal("error add registered structs", dm.Structs)
+	}
+}
+
+func TestRegisterPanic(t *testing.T) {
+	registerTestGroup(t, "test_register_panic", Test1{})
+
+	for _, structs := range [][]interface{}{
+		{nil},
+		{&Test1{}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("error register %v without panic", structs)
+				}
+			}()
+			RegisterGroup("test_register_panic", structs...)
+		}()
+	}
+}
@@ -0,0 +1,104 @@
+package ddlmaker
+
+import (
+	"github.com/kayac/ddl-maker/dialect"
+)
+
+// foreignKeysTable is a table whose foreign keys in a cycle are removed
+// to add them after all tables are created.
+type foreignKeysTable struct {
+	dialect.Table
+	foreignKeys dialect.ForeignKeys
+}
+
+func (t foreignKeysTable) ForeignKeys() dialect.ForeignKeys {
+	return t.foreignKeys
+}
+
+// sortTables sorts tables so that referenced tables come first, keeping the original order as much as possible.
+// When deferCycles is true, the foreign keys which make a cycle are removed from the tables,
+// and returned as the differences to add them after all tables are created.
+func sortTables(tables []dialect.Table, deferCycles bool) ([]dialect.Table, []tableDiff) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	tableMap := make(map[string]dialect.Table, len(tables))
+	for _, t := range tables {
+		tableMap[t.Name()] = t
+	}
+
+	state := make(map[string]int, len(tables))
+	cyclic := make(map[string]map[int]bool)
+	var sorted []dialect.Table
+
+	var visit func(t dialect.Table)
+	visit = func(t dialect.Table) {
+		state[t.Name()] = visiting
+		for i, fk := range t.ForeignKeys() {
+			refName := t.Dialect().Quote(fk.ReferenceTableName())
+			ref, ok := tableMap[refName]
+			if !ok || refName == t.Name() {
+				// unknown table and self reference do not need an order
+				continue
+			}
+
+			switch state[refName] {
+			case unvisited:
+				visit(ref)
+			case visiting:
+				if cyclic[t.Name()] == nil {
+					cyclic[t.Name()] = make(map[int]bool)
+				}
+				cyclic[t.Name()][i] = true
+			}
+		}
+		state[t.Name()] = visited
+		sorted = append(sorted, t)
+	}
+
+	for _, t := range tables {
+		if state[t.Name()] == unvisited {
+			visit(t)
+		}
+	}
+
+	if !deferCycles {
+		return sorted, nil
+	}
+
+	var deferred []tableDiff
+	for i, t := range sorted {
+		if len(cyclic[t.Name()]) == 0 {
+			continue
+		}
+
+		var foreignKeys, cyclicForeignKeys dialect.ForeignKeys
+		for j, fk := range t.ForeignKeys() {
+			if cyclic[t.Name()][j] {
+				cyclicForeignKeys = append(cyclicForeignKeys, fk)
+			} else {
+				foreignKeys = append(foreignKeys, fk)
+			}
+		}
+		sorted[i] = foreignKeysTable{Table: t, foreignKeys: foreignKeys}
+		deferred = append(deferred, tableDiff{
+			name:           t.Name(),
+			addForeignKeys: cyclicForeignKeys,
+			dialect:        t.Dialect(),
+		})
+	}
+
+	return sorted, deferred
+}
+
+// deferCycles reports whether the foreign keys in a cycle must be added after all tables are created
+func deferCycles(d dialect.Dialect) bool {
+	if fr, ok := d.(dialect.ForwardReference); ok {
+		return !fr.AllowForwardReference()
+	}
+
+	return true
+}
@@ -0,0 +1,149 @@
+package ddlmaker
+
+import (
+	"testing"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/postgres"
+)
+
+type SortComment struct {
+	ID      int64
+	EntryID int64
+}
+
+func (c SortComment) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (c SortComment) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		postgres.AddForeignKey([]string{"entry_id"}, []string{"id"}, "sort_entry"),
+	}
+}
+
+type SortEntry struct {
+	ID       int64
+	AuthorID int64
+}
+
+func (e SortEntry) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (e SortEntry) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		postgres.AddForeignKey([]string{"author_id"}, []string{"id"}, "sort_author"),
+	}
+}
+
+type SortAuthor struct {
+	ID          int64
+	BestEntryID int64
+	ParentID    int64
+}
+
+func (a SortAuthor) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (a SortAuthor) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		postgres.AddForeignKey([]string{"best_entry_id"}, []string{"id"}, "sort_entry"),
+		postgres.AddForeignKey([]string{"parent_id"}, []string{"id"}, "sort_author"),
+	}
+}
+
+func TestGenerateSortTables(t *testing.T) {
+	generatedDDL := `BEGIN;
+
+DROP TABLE IF EXISTS "sort_author" CASCADE;
+
+CREATE TABLE "sort_author" (
+    "id" BIGINT NOT NULL,
+    "best_entry_id" BIGINT NOT NULL,
+    "parent_id" BIGINT NOT NULL,
+    FOREIGN KEY ("parent_id") REFERENCES "sort_author" ("id"),
+    PRIMARY KEY ("id")
+);
+
+
+DROP TABLE IF EXISTS "sort_entry" CASCADE;
+
+CREATE TABLE "sort_entry" (
+    "id" BIGINT NOT NULL,
+    "author_id" BIGINT NOT NULL,
+    FOREIGN KEY ("author_id") REFERENCES "sort_author" ("id"),
+    PRIMARY KEY ("id")
+);
+
+
+DROP TABLE IF EXISTS "sort_comment" CASCADE;
+
+CREATE TABLE "sort_comment" (
+    "id" BIGINT NOT NULL,
+    "entry_id" BIGINT NOT NULL,
+    FOREIGN KEY ("entry_id") REFERENCES "sort_entry" ("id"),
+    PRIMARY KEY ("id")
+);
+
+
+ALTER TABLE "sort_author" ADD FOREIGN KEY ("best_entry_id") REFERENCES "sort_entry" ("id");
+
+COMMIT;
+`
+
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "postgres"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(SortComment{}, SortAuthor{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.AddStruct(SortEntry{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if ddl != generatedDDL {
+		t.Fatalf("generatedDDL: %s \n checkDDLL: %s \n", ddl, generatedDDL)
+	}
+}
+
+func TestSortTablesForwardReference(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(SortComment{}, SortAuthor{}, SortEntry{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	err = dm.parse()
+	if err != nil {
+		t.Fatal("error parse", err)
+	}
+
+	tables, deferred := sortTables(dm.Tables, deferCycles(dm.Dialect))
+	if len(deferred) != 0 {
+		t.Fatal("error foreign keys in a cycle are deferred", len(deferred))
+	}
+	order := []string{"`sort_author`", "`sort_entry`", "`sort_comment`"}
+	for i, table := range tables {
+		if table.Name() != order[i] {
+			t.Fatalf("error sort tables. %d: %s", i, table.Name())
+		}
+		if len(table.ForeignKeys()) == 0 {
+			t.Fatalf("error foreign keys of %s are removed", table.Name())
+		}
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/kayac/ddl-maker/static
+
+go 1.22.0
+
+require (
+	github.com/kayac/ddl-maker v0.2.0
+	github.com/pkg/errors v0.9.1
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	github.com/nxadm/tail v1.4.11 // indirect
+	github.com/serenize/snaker v0.0.0-20171204205717-a683aaf2d516 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
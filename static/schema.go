@@ -0,0 +1,144 @@
+package static
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	gotoken "go/token"
+	"go/types"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	ddlmaker "github.com/kayac/ddl-maker"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// SCHEMAFILENAME is the file written by GenerateSchemaTo in go generate
+	SCHEMAFILENAME = "zz_ddl.go"
+)
+
+var schemaTemplate = template.Must(template.New("schema").Parse(`// Code generated by ddl-maker gen; DO NOT EDIT.
+
+package {{ .Package }}
+
+// Schema returns the structs of the tables in the package, which are passed to ddlmaker.AddStruct
+func Schema() []interface{} {
+	return []interface{}{
+		{{- range .Structs }}
+		{{ . }},
+		{{- end }}
+	}
+}
+`))
+
+// GenerateSchemaTo writes the Go source of the package in dir, which declares func Schema() []interface{} of its tables, to w.
+// The tables are the exported structs which have a PrimaryKey method, or the structs marked with //ddl:table if any.
+// A struct is listed as a pointer if it has a method of a pointer receiver.
+// The existing SCHEMAFILENAME of the package is ignored, so that it is generated again even if it is out of date.
+// It returns an error for a struct which has annotations other than //ddl:table, which AddStruct does not read.
+func GenerateSchemaTo(w io.Writer, dir string) error {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo |
+			packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+		ParseFile: func(fset *gotoken.FileSet, filename string, src []byte) (*ast.File, error) {
+			mode := parser.ParseComments
+			if filepath.Base(filename) == SCHEMAFILENAME {
+				mode = parser.PackageClauseOnly
+			}
+			return parser.ParseFile(fset, filename, src, mode)
+		},
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return errors.Wrap(err, "error load package")
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("error load package: %d packages are found in %s", len(pkgs), dir)
+	}
+	pkg := pkgs[0]
+
+	var errs ddlmaker.Errors
+	for _, e := range pkg.Errors {
+		errs = append(errs, errors.Wrapf(e, "error load package %s", pkg.PkgPath))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	l := newLoader(pkgs, nil)
+	structs, marked := l.structs(pkg)
+
+	var names []string
+	for _, s := range structs {
+		ptr := types.NewPointer(s.named)
+		if !marked && lookupMethod(types.NewMethodSet(ptr), "PrimaryKey") == nil {
+			continue
+		}
+		if err := checkSchemaAnnotations(s); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error generate schema %s.%s", pkg.PkgPath, s.named.Obj().Name()))
+			continue
+		}
+
+		name := s.named.Obj().Name() + "{}"
+		if types.NewMethodSet(s.named).Len() < types.NewMethodSet(ptr).Len() {
+			name = "&" + name
+		}
+		names = append(names, name)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	var buf bytes.Buffer
+	err = schemaTemplate.Execute(&buf, struct {
+		Package string
+		Structs []string
+	}{
+		Package: pkg.Name,
+		Structs: names,
+	})
+	if err != nil {
+		return errors.Wrap(err, "template execute error")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "error format schema")
+	}
+	if _, err := w.Write(src); err != nil {
+		return errors.Wrap(err, "error write schema")
+	}
+
+	return nil
+}
+
+// checkSchemaAnnotations returns an error if s has annotations which AddStruct ignores,
+// such as //ddl:primarykey or the table name of //ddl:table, so that Schema() never defines another table.
+func checkSchemaAnnotations(s structDecl) error {
+	var names []string
+	for name, args := range s.annotations {
+		if name == "table" {
+			if len(args) == 0 {
+				continue
+			}
+			name += " " + args[0]
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	return fmt.Errorf("//ddl:%s is not supported by Schema(), declare the methods such as Table(), PrimaryKey() and Indexes() instead",
+		strings.Join(names, ", //ddl:"))
+}
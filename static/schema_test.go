@@ -0,0 +1,51 @@
+package static
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSchemaTo(t *testing.T) {
+	expected := `// Code generated by ddl-maker gen; DO NOT EDIT.
+
+package models
+
+// Schema returns the structs of the tables in the package, which are passed to ddlmaker.AddStruct
+func Schema() []interface{} {
+	return []interface{}{
+		Author{},
+		&Entry{},
+	}
+}
+`
+
+	var buf bytes.Buffer
+	if err := GenerateSchemaTo(&buf, "./testdata/models"); err != nil {
+		t.Fatal("error generate schema", err)
+	}
+	if buf.String() != expected {
+		t.Fatalf("generated schema: %s \n expected: %s \n", buf.String(), expected)
+	}
+}
+
+func TestGenerateSchemaToOutOfDate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateSchemaTo(&buf, "./testdata/outofdate"); err != nil {
+		t.Fatal("error generate schema", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("return []interface{}{\n\t\t&User{},\n\t}")) {
+		t.Fatal("error generate schema of out of date package", buf.String())
+	}
+}
+
+func TestGenerateSchemaToAnnotated(t *testing.T) {
+	var buf bytes.Buffer
+	err := GenerateSchemaTo(&buf, "./testdata/annotated")
+	if err == nil || !strings.Contains(err.Error(), "annotated.Tag: //ddl:index, //ddl:primarykey, //ddl:table tags, //ddl:unique is not supported") {
+		t.Fatal("error generate schema of annotated structs", err)
+	}
+	if buf.Len() > 0 {
+		t.Fatal("error write schema of annotated structs", buf.String())
+	}
+}
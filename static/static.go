@@ -0,0 +1,811 @@
+// Package static loads the tables from the source of Go packages without compiling them, with go/packages.
+package static
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	gotoken "go/token"
+	"go/types"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	ddlmaker "github.com/kayac/ddl-maker"
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// ANNOTATIONPREFIX is the prefix of the annotations in the doc comment of a struct, such as //ddl:table
+	ANNOTATIONPREFIX = "//ddl:"
+)
+
+var (
+	// dialectFuncs are the functions which can be called in the table methods of a loaded package, keyed by the full name
+	dialectFuncs = funcsByName(
+		mysql.AddIndex, mysql.AddUniqueIndex, mysql.AddFullTextIndex, mysql.AddSpatialIndex, mysql.AddPrimaryKey,
+		mysql.AddForeignKey, mysql.WithForeignKeyName, mysql.WithUpdateForeignKeyOption, mysql.WithDeleteForeignKeyOption,
+		mysql.AddCheck, mysql.AddPartition, mysql.PartitionByRange, mysql.PartitionByRangeColumns,
+		mysql.PartitionByList, mysql.PartitionByListColumns, mysql.PartitionByHash, mysql.PartitionByKey,
+		postgres.AddIndex, postgres.AddUniqueIndex, postgres.AddPrimaryKey,
+		postgres.AddForeignKey, postgres.WithForeignKeyName, postgres.WithUpdateForeignKeyOption, postgres.WithDeleteForeignKeyOption,
+		postgres.AddCheck,
+	)
+
+	// dialectVars are the variables which can be referred in the table methods of a loaded package
+	dialectVars = map[string]interface{}{
+		"github.com/kayac/ddl-maker/dialect/mysql.ForeignKeyOptionCascade":       mysql.ForeignKeyOptionCascade,
+		"github.com/kayac/ddl-maker/dialect/mysql.ForeignKeyOptionSetNull":       mysql.ForeignKeyOptionSetNull,
+		"github.com/kayac/ddl-maker/dialect/mysql.ForeignKeyOptionRestrict":      mysql.ForeignKeyOptionRestrict,
+		"github.com/kayac/ddl-maker/dialect/mysql.ForeignKeyOptionNoAction":      mysql.ForeignKeyOptionNoAction,
+		"github.com/kayac/ddl-maker/dialect/mysql.ForeignKeyOptionSetDefault":    mysql.ForeignKeyOptionSetDefault,
+		"github.com/kayac/ddl-maker/dialect/postgres.ForeignKeyOptionCascade":    postgres.ForeignKeyOptionCascade,
+		"github.com/kayac/ddl-maker/dialect/postgres.ForeignKeyOptionSetNull":    postgres.ForeignKeyOptionSetNull,
+		"github.com/kayac/ddl-maker/dialect/postgres.ForeignKeyOptionRestrict":   postgres.ForeignKeyOptionRestrict,
+		"github.com/kayac/ddl-maker/dialect/postgres.ForeignKeyOptionNoAction":   postgres.ForeignKeyOptionNoAction,
+		"github.com/kayac/ddl-maker/dialect/postgres.ForeignKeyOptionSetDefault": postgres.ForeignKeyOptionSetDefault,
+	}
+
+	// signatures are the signatures of the methods which are used, in the same types as the interfaces of ddlmaker
+	signatures = map[string]string{
+		"Table":        "() (string)",
+		"Comment":      "() (string)",
+		"PrimaryKey":   "() (github.com/kayac/ddl-maker/dialect.PrimaryKey)",
+		"ForeignKeys":  "() (github.com/kayac/ddl-maker/dialect.ForeignKeys)",
+		"Indexes":      "() (github.com/kayac/ddl-maker/dialect.Indexes)",
+		"Checks":       "() (github.com/kayac/ddl-maker/dialect.Checks)",
+		"TableOptions": "() (github.com/kayac/ddl-maker/dialect.TableOptions)",
+		"Partitioning": "() (github.com/kayac/ddl-maker/dialect.Partitioning)",
+		"EnumValues":   "() ([]string)",
+		"DDLType":      "() (string)",
+		"Value":        "() (database/sql/driver.Value, error)",
+		"Scan":         "(any) (error)",
+	}
+
+	stringType       = reflect.TypeOf("")
+	stringsType      = reflect.TypeOf([]string{})
+	primaryKeyType   = reflect.TypeOf((*dialect.PrimaryKey)(nil)).Elem()
+	foreignKeysType  = reflect.TypeOf(dialect.ForeignKeys{})
+	indexesType      = reflect.TypeOf(dialect.Indexes{})
+	checksType       = reflect.TypeOf(dialect.Checks{})
+	tableOptionsType = reflect.TypeOf(dialect.TableOptions{})
+	partitioningType = reflect.TypeOf((*dialect.Partitioning)(nil)).Elem()
+)
+
+func funcsByName(funcs ...interface{}) map[string]reflect.Value {
+	m := make(map[string]reflect.Value, len(funcs))
+	for _, f := range funcs {
+		v := reflect.ValueOf(f)
+		m[runtime.FuncForPC(v.Pointer()).Name()] = v
+	}
+
+	return m
+}
+
+// AddPackages loads the packages matched by patterns, such as "./models/...", with go/packages without compiling them,
+// and adds their exported structs which have a primary key as tables. If some structs of a package are marked with //ddl:table,
+// only they are added.
+//
+// The table methods such as PrimaryKey() and Indexes() are evaluated statically, so they must consist of a return statement
+// with calls of the dialect functions such as mysql.AddIndex and constants.
+// The doc comment of a struct can also have the annotations //ddl:primarykey col,..., //ddl:index name col,... and //ddl:unique name col,....
+func AddPackages(dm *ddlmaker.DDLMaker, patterns ...string) error {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo |
+			packages.NeedImports | packages.NeedDeps,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return errors.Wrap(err, "error load packages")
+	}
+
+	var errs ddlmaker.Errors
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			errs = append(errs, errors.Wrapf(e, "error load package %s", pkg.PkgPath))
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+
+	l := newLoader(pkgs, dm.Dialect)
+	for _, pkg := range pkgs {
+		tables, pkgErrs := l.parsePackage(pkg)
+		errs = append(errs, pkgErrs...)
+		if err := dm.AddTables(tables...); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// loader builds tables from the syntax and the types of loaded packages
+type loader struct {
+	fset    *gotoken.FileSet
+	dialect dialect.Dialect
+	// funcs are the function declarations keyed by the position of the name
+	funcs map[gotoken.Pos]funcDecl
+}
+
+type funcDecl struct {
+	decl *ast.FuncDecl
+	info *types.Info
+}
+
+// structDecl is a struct declaration with the annotations of its doc comment
+type structDecl struct {
+	named       *types.Named
+	annotations map[string][]string
+}
+
+func newLoader(pkgs []*packages.Package, d dialect.Dialect) *loader {
+	l := &loader{
+		dialect: d,
+		funcs:   make(map[gotoken.Pos]funcDecl),
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if l.fset == nil {
+			l.fset = pkg.Fset
+		}
+		for _, f := range pkg.Syntax {
+			for _, decl := range f.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					l.funcs[fd.Name.Pos()] = funcDecl{decl: fd, info: pkg.TypesInfo}
+				}
+			}
+		}
+	})
+
+	return l
+}
+
+func (l *loader) parsePackage(pkg *packages.Package) ([]dialect.Table, ddlmaker.Errors) {
+	var errs ddlmaker.Errors
+	var tables []dialect.Table
+
+	structs, marked := l.structs(pkg)
+	for _, s := range structs {
+		path := fmt.Sprintf("%s.%s", pkg.Name, s.named.Obj().Name())
+		if !marked {
+			// an unmarked struct without primary key is not a table, such as an embedded struct or a DTO
+			ok, err := l.hasPrimaryKey(s)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "error parse table %s", path))
+				continue
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		def, err := l.parseTable(s)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse table %s", path))
+			continue
+		}
+		st := s.named.Underlying().(*types.Struct)
+		columns, fieldErrs := l.parseFields(st, path, "", []types.Type{s.named})
+		errs = append(errs, fieldErrs...)
+		def.Columns = columns
+		tables = append(tables, ddlmaker.NewTable(def, l.dialect))
+	}
+
+	return tables, errs
+}
+
+// hasPrimaryKey reports whether s has //ddl:primarykey or PrimaryKey() which returns a primary key
+func (l *loader) hasPrimaryKey(s structDecl) (bool, error) {
+	if _, ok := s.annotations["primarykey"]; ok {
+		return true, nil
+	}
+	fn := lookupMethod(types.NewMethodSet(types.NewPointer(s.named)), "PrimaryKey")
+	if fn == nil {
+		return false, nil
+	}
+	v, err := l.evalMethod(fn, primaryKeyType)
+	if err != nil {
+		return false, err
+	}
+
+	return !v.IsNil(), nil
+}
+
+// structs returns the exported structs of pkg in the order of the files and the declarations,
+// which are only the structs marked with //ddl:table if any.
+func (l *loader) structs(pkg *packages.Package) ([]structDecl, bool) {
+	files := append([]*ast.File(nil), pkg.Syntax...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return l.fset.File(files[i].Pos()).Name() < l.fset.File(files[j].Pos()).Name()
+	})
+
+	var all, marked []structDecl
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != gotoken.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.StructType); !ok || !ts.Name.IsExported() || ts.TypeParams != nil {
+					continue
+				}
+				named, ok := pkg.TypesInfo.Defs[ts.Name].Type().(*types.Named)
+				if !ok {
+					continue
+				}
+
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				s := structDecl{named: named, annotations: parseAnnotations(doc)}
+				all = append(all, s)
+				if _, ok := s.annotations["table"]; ok {
+					marked = append(marked, s)
+				}
+			}
+		}
+	}
+
+	if len(marked) > 0 {
+		return marked, true
+	}
+	return all, false
+}
+
+// parseAnnotations parses the lines such as //ddl:index name col1,col2 into the map of the name and the arguments
+func parseAnnotations(doc *ast.CommentGroup) map[string][]string {
+	annotations := make(map[string][]string)
+	if doc == nil {
+		return annotations
+	}
+
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, ANNOTATIONPREFIX) {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(c.Text, ANNOTATIONPREFIX))
+		if len(fields) == 0 {
+			continue
+		}
+		name := fields[0]
+		// repeated annotations such as //ddl:index are separated by an empty argument
+		if _, ok := annotations[name]; ok {
+			annotations[name] = append(annotations[name], "")
+		}
+		annotations[name] = append(annotations[name], fields[1:]...)
+	}
+
+	return annotations
+}
+
+// parseFields parses the fields of st into columns in the same way as ddlmaker parses the fields of reflect.Type
+func (l *loader) parseFields(st *types.Struct, path, prefix string, parents []types.Type) ([]dialect.Column, ddlmaker.Errors) {
+	var errs ddlmaker.Errors
+	var columns []dialect.Column
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		fieldPath := fmt.Sprintf("%s.%s", path, field.Name())
+		// the unexported fields of a flattened struct, such as the fields of a struct in another package, are not columns
+		if len(parents) > 1 && !field.Exported() && !field.Embedded() {
+			continue
+		}
+		tag := strings.TrimSpace(reflect.StructTag(st.Tag(i)).Get(ddlmaker.TAGPREFIX))
+
+		ft := types.Unalias(field.Type())
+		if p, ok := ft.(*types.Pointer); ok {
+			ft = types.Unalias(p.Elem())
+		}
+		nested, isStruct := ft.Underlying().(*types.Struct)
+		isStruct = isStruct && !l.isColumnType(ft)
+		fieldPrefix, ok, err := ddlmaker.FlattenTag(tag, field.Embedded(), isStruct)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		if ok {
+			recursive := false
+			for _, parent := range parents {
+				if types.Identical(parent, ft) {
+					recursive = true
+				}
+			}
+			if recursive {
+				errs = append(errs, fmt.Errorf("error parse field %s: %s is embedded recursively", fieldPath, ft))
+				continue
+			}
+			cs, fieldErrs := l.parseFields(nested, fieldPath, prefix+fieldPrefix, append(parents, ft))
+			errs = append(errs, fieldErrs...)
+			columns = append(columns, cs...)
+			continue
+		}
+
+		column, err := l.parseField(field, tag, prefix)
+		if err != nil {
+			if err == ddlmaker.ErrIgnoreField {
+				continue
+			}
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		if _, err := column.ToSQL(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error parse field %s", fieldPath))
+			continue
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, errs
+}
+
+// isColumnType reports whether the struct type t is a single column in the same way as ddlmaker,
+// which is mapped to a type by the dialect, implements driver.Valuer or sql.Scanner, or declares DDLType
+func (l *loader) isColumnType(t types.Type) bool {
+	if _, err := l.dialect.ToSQL(typeString(t), 0); err == nil {
+		return true
+	}
+	methods := types.NewMethodSet(types.NewPointer(t))
+	for _, name := range []string{"DDLType", "Value", "Scan"} {
+		if lookupMethod(methods, name) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseField parses field into a column in the same way as ddlmaker parses reflect.StructField
+func (l *loader) parseField(field *types.Var, tag, prefix string) (dialect.Column, error) {
+	ft := types.Unalias(field.Type())
+	f := ddlmaker.Field{
+		Name:     field.Name(),
+		TypeName: typeString(ft),
+		Tag:      tag,
+	}
+	// the tag is checked before the methods of an ignored field are evaluated
+	if _, err := ddlmaker.NewColumn(f, prefix, l.dialect); err != nil {
+		return nil, err
+	}
+
+	elemType := ft
+	_, isPtr := ft.(*types.Pointer)
+	if isPtr {
+		elemType = types.Unalias(ft.(*types.Pointer).Elem())
+	}
+	methods := types.NewMethodSet(types.NewPointer(elemType))
+
+	isEnum := false
+	if fn := lookupMethod(methods, "EnumValues"); fn != nil {
+		v, err := l.evalMethod(fn, stringsType)
+		if err != nil {
+			return nil, err
+		}
+		f.Values = v.Interface().([]string)
+		isEnum = true
+	}
+	if fn := lookupMethod(methods, "DDLType"); fn != nil {
+		v, err := l.evalMethod(fn, stringType)
+		if err != nil {
+			return nil, err
+		}
+		f.SQLType = v.String()
+	} else if _, err := l.dialect.ToSQL(f.TypeName, 0); err != nil && (isEnum || lookupMethod(methods, "Value") != nil || lookupMethod(methods, "Scan") != nil) {
+		// a type implementing driver.Valuer, sql.Scanner or EnumValues is stored as its underlying kind
+		if kindName, ok := kindTypeName(elemType); ok {
+			if isPtr {
+				kindName = "*" + kindName
+			}
+			f.TypeName = kindName
+		}
+	}
+
+	return ddlmaker.NewColumn(f, prefix, l.dialect)
+}
+
+// typeString returns the name of t in the same format as reflect.Type.String()
+func typeString(t types.Type) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		if t.Obj().Pkg() == nil {
+			return t.Obj().Name()
+		}
+		return fmt.Sprintf("%s.%s", t.Obj().Pkg().Name(), t.Obj().Name())
+	case *types.Basic:
+		// byte and rune are uint8 and int32 in reflect
+		return types.Typ[t.Kind()].Name()
+	case *types.Pointer:
+		return "*" + typeString(t.Elem())
+	case *types.Slice:
+		return "[]" + typeString(t.Elem())
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeString(t.Elem()))
+	case *types.Map:
+		return fmt.Sprintf("map[%s]%s", typeString(t.Key()), typeString(t.Elem()))
+	}
+
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// kindTypeName returns the type name of the underlying kind of t in the same way as ddlmaker
+func kindTypeName(t types.Type) (string, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Bool, types.String, types.Float32, types.Float64,
+			types.Int8, types.Int16, types.Int32, types.Int64,
+			types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+			return types.Typ[u.Kind()].Name(), true
+		case types.Int:
+			return "int64", true
+		case types.Uint:
+			return "uint64", true
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return "[]uint8", true
+		}
+	}
+
+	return "", false
+}
+
+// lookupMethod returns the method of the name whose signature is the one of signatures,
+// so that an unrelated method of the same name, such as Comment() *Comment, is ignored as reflection does
+func lookupMethod(methods *types.MethodSet, name string) *types.Func {
+	for i := 0; i < methods.Len(); i++ {
+		fn, ok := methods.At(i).Obj().(*types.Func)
+		if !ok || fn.Name() != name {
+			continue
+		}
+		if sig, ok := fn.Type().(*types.Signature); ok && signatureString(sig) == signatures[name] {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+// signatureString returns the types of the parameters and the results of sig, such as "(any) (error)"
+func signatureString(sig *types.Signature) string {
+	tuple := func(t *types.Tuple) string {
+		var ts []string
+		for i := 0; i < t.Len(); i++ {
+			typ := t.At(i).Type()
+			if iface, ok := types.Unalias(typ).(*types.Interface); ok && iface.Empty() {
+				ts = append(ts, "any")
+				continue
+			}
+			ts = append(ts, types.TypeString(typ, nil))
+		}
+		return "(" + strings.Join(ts, ", ") + ")"
+	}
+	if sig.Variadic() || sig.TypeParams() != nil {
+		return ""
+	}
+
+	return tuple(sig.Params()) + " " + tuple(sig.Results())
+}
+
+// parseTable builds the table of s from its methods and annotations in the same way as parseTable of ddlmaker
+func (l *loader) parseTable(s structDecl) (ddlmaker.TableDef, error) {
+	methods := types.NewMethodSet(types.NewPointer(s.named))
+
+	tableName := s.named.Obj().Name()
+	if args := s.annotations["table"]; len(args) > 0 {
+		tableName = args[0]
+	}
+	var comment string
+	var primaryKey dialect.PrimaryKey
+	var foreignKeys dialect.ForeignKeys
+	var indexes dialect.Indexes
+	var checks dialect.Checks
+	var options dialect.TableOptions
+	var partitioning dialect.Partitioning
+
+	evals := []struct {
+		method string
+		typ    reflect.Type
+		set    func(v reflect.Value)
+	}{
+		{"Table", stringType, func(v reflect.Value) { tableName = v.String() }},
+		{"Comment", stringType, func(v reflect.Value) { comment = v.String() }},
+		{"PrimaryKey", primaryKeyType, func(v reflect.Value) { primaryKey, _ = v.Interface().(dialect.PrimaryKey) }},
+		{"ForeignKeys", foreignKeysType, func(v reflect.Value) { foreignKeys = v.Interface().(dialect.ForeignKeys) }},
+		{"Indexes", indexesType, func(v reflect.Value) { indexes = v.Interface().(dialect.Indexes) }},
+		{"Checks", checksType, func(v reflect.Value) { checks = v.Interface().(dialect.Checks) }},
+		{"TableOptions", tableOptionsType, func(v reflect.Value) { options = v.Interface().(dialect.TableOptions) }},
+		{"Partitioning", partitioningType, func(v reflect.Value) { partitioning, _ = v.Interface().(dialect.Partitioning) }},
+	}
+	for _, e := range evals {
+		fn := lookupMethod(methods, e.method)
+		if fn == nil {
+			continue
+		}
+		v, err := l.evalMethod(fn, e.typ)
+		if err != nil {
+			return ddlmaker.TableDef{}, err
+		}
+		e.set(v)
+	}
+
+	if columns, ok := s.annotations["primarykey"]; ok {
+		if primaryKey != nil {
+			return ddlmaker.TableDef{}, fmt.Errorf("both PrimaryKey() and //ddl:primarykey are declared")
+		}
+		primaryKey = l.primaryKey(splitColumns(strings.Join(columns, ""))...)
+	}
+	for _, kind := range []string{"index", "unique"} {
+		args := s.annotations[kind]
+		for len(args) > 0 {
+			end := 0
+			for end < len(args) && args[end] != "" {
+				end++
+			}
+			if end != 2 {
+				return ddlmaker.TableDef{}, fmt.Errorf("//ddl:%s must have a name and columns", kind)
+			}
+			indexes = append(indexes, l.index(kind == "unique", args[0], splitColumns(args[1])...))
+			if end < len(args) {
+				end++
+			}
+			args = args[end:]
+		}
+	}
+
+	return ddlmaker.TableDef{
+		Name:         tableName,
+		Comment:      comment,
+		PrimaryKey:   primaryKey,
+		ForeignKeys:  foreignKeys,
+		Indexes:      indexes,
+		Checks:       checks,
+		Options:      options,
+		Partitioning: partitioning,
+	}, nil
+}
+
+func splitColumns(s string) []string {
+	var columns []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			columns = append(columns, c)
+		}
+	}
+
+	return columns
+}
+
+// primaryKey returns the primary key of an annotation, which is the type of postgres for postgres and mysql for the others
+func (l *loader) primaryKey(columns ...string) dialect.PrimaryKey {
+	switch l.dialect.(type) {
+	case postgres.PostgreSQL, *postgres.PostgreSQL:
+		return postgres.AddPrimaryKey(columns...)
+	}
+	return mysql.AddPrimaryKey(columns...)
+}
+
+// index returns the index of an annotation, which is the type of postgres for postgres and mysql for the others
+func (l *loader) index(unique bool, name string, columns ...string) dialect.Index {
+	switch l.dialect.(type) {
+	case postgres.PostgreSQL, *postgres.PostgreSQL:
+		if unique {
+			return postgres.AddUniqueIndex(name, columns...)
+		}
+		return postgres.AddIndex(name, columns...)
+	}
+	if unique {
+		return mysql.AddUniqueIndex(name, columns...)
+	}
+	return mysql.AddIndex(name, columns...)
+}
+
+// evalMethod evaluates the method body which consists of a return statement
+func (l *loader) evalMethod(fn *types.Func, typ reflect.Type) (reflect.Value, error) {
+	f, ok := l.funcs[fn.Pos()]
+	if !ok || f.decl.Body == nil {
+		return reflect.Value{}, fmt.Errorf("body of %s is not found", fn.FullName())
+	}
+	if len(f.decl.Body.List) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s: %s must consist of a return statement", l.fset.Position(f.decl.Pos()), fn.FullName())
+	}
+	ret, ok := f.decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s: %s must consist of a return statement", l.fset.Position(f.decl.Pos()), fn.FullName())
+	}
+
+	return l.eval(ret.Results[0], f.info, typ)
+}
+
+// eval evaluates expr into a value of typ, or a value of its own type if typ is nil
+func (l *loader) eval(expr ast.Expr, info *types.Info, typ reflect.Type) (reflect.Value, error) {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+		return l.convert(expr, constantValue(tv.Value), typ)
+	}
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return l.eval(e.X, info, typ)
+	case *ast.Ident:
+		if e.Name == "nil" && typ != nil {
+			return reflect.Zero(typ), nil
+		}
+		return l.evalVar(e, info.Uses[e], typ)
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[e]; ok && sel.Kind() == types.MethodVal {
+			return reflect.Value{}, l.errorf(e, "method value %s is not supported", e.Sel.Name)
+		}
+		return l.evalVar(e, info.Uses[e.Sel], typ)
+	case *ast.CompositeLit:
+		return l.evalCompositeLit(e, info, typ)
+	case *ast.CallExpr:
+		return l.evalCall(e, info, typ)
+	}
+
+	return reflect.Value{}, l.errorf(expr, "unsupported expression %T", expr)
+}
+
+func (l *loader) evalVar(expr ast.Expr, obj types.Object, typ reflect.Type) (reflect.Value, error) {
+	v, ok := obj.(*types.Var)
+	if !ok || v.Pkg() == nil {
+		return reflect.Value{}, l.errorf(expr, "unsupported identifier")
+	}
+	value, ok := dialectVars[v.Pkg().Path()+"."+v.Name()]
+	if !ok {
+		return reflect.Value{}, l.errorf(expr, "variable %s.%s is not supported", v.Pkg().Name(), v.Name())
+	}
+
+	return l.convert(expr, reflect.ValueOf(value), typ)
+}
+
+func (l *loader) evalCompositeLit(lit *ast.CompositeLit, info *types.Info, typ reflect.Type) (reflect.Value, error) {
+	if typ == nil || (typ.Kind() != reflect.Slice && typ.Kind() != reflect.Map) {
+		return reflect.Value{}, l.errorf(lit, "composite literal of %s is not supported", info.TypeOf(lit))
+	}
+
+	if typ.Kind() == reflect.Map {
+		m := reflect.MakeMapWithSize(typ, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return reflect.Value{}, l.errorf(elt, "map element must have a key")
+			}
+			k, err := l.eval(kv.Key, info, typ.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v, err := l.eval(kv.Value, info, typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			m.SetMapIndex(k, v)
+		}
+		return m, nil
+	}
+
+	s := reflect.MakeSlice(typ, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		if _, ok := elt.(*ast.KeyValueExpr); ok {
+			return reflect.Value{}, l.errorf(elt, "indexed slice element is not supported")
+		}
+		v, err := l.eval(elt, info, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		s = reflect.Append(s, v)
+	}
+
+	return s, nil
+}
+
+func (l *loader) evalCall(call *ast.CallExpr, info *types.Info, typ reflect.Type) (reflect.Value, error) {
+	var fn reflect.Value
+	switch f := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[f]; ok && sel.Kind() == types.MethodVal {
+			// method of a value returned by a dialect function, such as mysql.AddFullTextIndex(...).WithParser("ngram")
+			recv, err := l.eval(f.X, info, nil)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			fn = recv.MethodByName(f.Sel.Name)
+			break
+		}
+		fn = l.lookupFunc(info.Uses[f.Sel])
+	case *ast.Ident:
+		fn = l.lookupFunc(info.Uses[f])
+	}
+	if !fn.IsValid() {
+		return reflect.Value{}, l.errorf(call, "unsupported function call")
+	}
+
+	ft := fn.Type()
+	var args []reflect.Value
+	for i, arg := range call.Args {
+		var at reflect.Type
+		switch {
+		case ft.IsVariadic() && i >= ft.NumIn()-1 && !call.Ellipsis.IsValid():
+			at = ft.In(ft.NumIn() - 1).Elem()
+		case i < ft.NumIn():
+			at = ft.In(i)
+		default:
+			return reflect.Value{}, l.errorf(call, "too many arguments")
+		}
+		v, err := l.eval(arg, info, at)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		args = append(args, v)
+	}
+
+	var results []reflect.Value
+	if call.Ellipsis.IsValid() {
+		results = fn.CallSlice(args)
+	} else {
+		results = fn.Call(args)
+	}
+	if len(results) != 1 {
+		return reflect.Value{}, l.errorf(call, "function must return a value")
+	}
+
+	return l.convert(call, results[0], typ)
+}
+
+func (l *loader) lookupFunc(obj types.Object) reflect.Value {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return reflect.Value{}
+	}
+
+	return dialectFuncs[fn.FullName()]
+}
+
+// convert converts v into typ, which is an interface implemented by v or a type convertible from v
+func (l *loader) convert(expr ast.Expr, v reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	switch {
+	case typ == nil || v.Type() == typ:
+		return v, nil
+	case typ.Kind() == reflect.Interface && v.Type().Implements(typ):
+		iv := reflect.New(typ).Elem()
+		iv.Set(v)
+		return iv, nil
+	case typ.Kind() != reflect.Interface && v.Type().ConvertibleTo(typ):
+		return v.Convert(typ), nil
+	}
+
+	return reflect.Value{}, l.errorf(expr, "%s can not be used as %s", v.Type(), typ)
+}
+
+func (l *loader) errorf(expr ast.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", l.fset.Position(expr.Pos()), fmt.Sprintf(format, args...))
+}
+
+// constantValue returns the value of a constant as string, bool, int64, uint64 or float64
+func constantValue(c constant.Value) reflect.Value {
+	switch c.Kind() {
+	case constant.String:
+		return reflect.ValueOf(constant.StringVal(c))
+	case constant.Bool:
+		return reflect.ValueOf(constant.BoolVal(c))
+	case constant.Int:
+		if i, ok := constant.Int64Val(c); ok {
+			return reflect.ValueOf(i)
+		}
+		u, _ := constant.Uint64Val(c)
+		return reflect.ValueOf(u)
+	}
+
+	f, _ := constant.Float64Val(c)
+	return reflect.ValueOf(f)
+}
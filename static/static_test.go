@@ -0,0 +1,92 @@
+package static
+
+import (
+	"strings"
+	"testing"
+
+	ddlmaker "github.com/kayac/ddl-maker"
+	"github.com/kayac/ddl-maker/static/testdata/models"
+)
+
+func TestAddPackages(t *testing.T) {
+	conf := ddlmaker.Config{
+		DB: ddlmaker.DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	}
+
+	dm, err := ddlmaker.New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = dm.AddStruct(models.Author{}, &models.Entry{})
+	if err != nil {
+		t.Fatal("error add struct", err)
+	}
+	expected, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	dm, err = ddlmaker.New(conf)
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = AddPackages(dm, "./testdata/models")
+	if err != nil {
+		t.Fatal("error add packages", err)
+	}
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+	if ddl != expected {
+		t.Fatalf("staticDDL: %s \n reflectDDL: %s \n", ddl, expected)
+	}
+}
+
+func TestAddPackagesAnnotated(t *testing.T) {
+	dm, err := ddlmaker.New(ddlmaker.Config{
+		DB: ddlmaker.DBConfig{Driver: "mysql", Engine: "InnoDB", Charset: "utf8mb4"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+	err = AddPackages(dm, "./testdata/annotated")
+	if err != nil {
+		t.Fatal("error add packages", err)
+	}
+	ddl, err := dm.GenerateString()
+	if err != nil {
+		t.Fatal("error generate ddl", err)
+	}
+
+	expected := "CREATE TABLE `tags` (\n" +
+		"    `id` BIGINT unsigned NOT NULL,\n" +
+		"    `kind` VARCHAR(16) NOT NULL,\n" +
+		"    `name` VARCHAR(64) NOT NULL,\n" +
+		"    INDEX `kind_name_idx` (`kind`, `name`),\n" +
+		"    UNIQUE `name_idx` (`name`),\n" +
+		"    PRIMARY KEY (`id`)\n"
+	if !strings.Contains(ddl, expected) || strings.Contains(ddl, "helper") {
+		t.Fatal("error generate annotated ddl", ddl)
+	}
+}
+
+func TestAddPackagesInvalid(t *testing.T) {
+	dm, err := ddlmaker.New(ddlmaker.Config{
+		DB: ddlmaker.DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal("error new maker", err)
+	}
+
+	err = AddPackages(dm, "./testdata/invalid")
+	if err == nil || !strings.Contains(err.Error(), "must consist of a return statement") {
+		t.Fatal("error add packages of complex method", err)
+	}
+	if !strings.Contains(err.Error(), "error parse field invalid.Setting.Params: map[string]string is not match") {
+		t.Fatal("error add packages of unsupported field type", err)
+	}
+	if err := AddPackages(dm, "./testdata/notfound"); err == nil {
+		t.Fatal("error add packages of unknown package")
+	}
+}
@@ -0,0 +1,19 @@
+// Package annotated is the models marked with annotations
+package annotated
+
+// Tag is a table
+//
+//ddl:table tags
+//ddl:primarykey id
+//ddl:unique name_idx name
+//ddl:index kind_name_idx kind,name
+type Tag struct {
+	ID   uint64
+	Kind string `ddl:"size=16"`
+	Name string `ddl:"size=64"`
+}
+
+// Helper is not marked
+type Helper struct {
+	Value string
+}
@@ -0,0 +1,29 @@
+// Package invalid is the models whose methods can not be evaluated statically
+package invalid
+
+import (
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+// User XXX
+type User struct {
+	ID uint64
+}
+
+// PrimaryKey XXX
+func (u User) PrimaryKey() dialect.PrimaryKey {
+	columns := []string{"id"}
+	return mysql.AddPrimaryKey(columns...)
+}
+
+// Setting has a field of an unsupported type
+type Setting struct {
+	ID     uint64
+	Params map[string]string
+}
+
+// PrimaryKey XXX
+func (s Setting) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
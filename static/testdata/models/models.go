@@ -0,0 +1,128 @@
+// Package models is the models loaded by the tests of AddPackages
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+// Status is stored as an enum
+type Status string
+
+// EnumValues XXX
+func (s Status) EnumValues() []string {
+	return []string{"draft", "published"}
+}
+
+// Token is stored as a string by driver.Valuer
+type Token string
+
+// Value XXX
+func (t Token) Value() (driver.Value, error) {
+	return string(t), nil
+}
+
+// Point declares its sql type
+type Point struct {
+	X, Y float64
+}
+
+// DDLType XXX
+func (p Point) DDLType() string {
+	return "POINT"
+}
+
+// Timestamps is embedded in tables
+type Timestamps struct {
+	CreatedAt time.Time `ddl:"default=CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `ddl:"default=CURRENT_TIMESTAMP,onupdate"`
+	loaded    bool
+}
+
+// Author XXX
+type Author struct {
+	ID   uint64 `ddl:"auto"`
+	Name string `ddl:"size=64,comment='the name, in full'"`
+	Timestamps
+	*time.Time `ddl:"null"`
+}
+
+// PrimaryKey XXX
+func (a Author) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+// Entry XXX
+type Entry struct {
+	ID       uint64 `ddl:"auto"`
+	AuthorID uint64
+	Title    string  `ddl:"size=191"`
+	Body     *string `ddl:"type=text"`
+	Raw      []byte  `ddl:"null"`
+	Status   Status
+	Token    Token `ddl:"size=16"`
+	Location Point
+	Price    float64    `ddl:"type=decimal,precision=12,scale=2"`
+	Origin   Timestamps `ddl:"prefix=origin_"`
+	Ignored  string     `ddl:"-"`
+}
+
+// Table XXX
+func (e Entry) Table() string {
+	return "blog_entry"
+}
+
+// Comment XXX
+func (e Entry) Comment() string {
+	return "entries of " + "authors"
+}
+
+// PrimaryKey XXX
+func (e Entry) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+// Indexes XXX
+func (e *Entry) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		mysql.AddUniqueIndex("title_idx", "title"),
+		mysql.AddFullTextIndex("body_idx", "body").WithParser("ngram"),
+	}
+}
+
+// ForeignKeys XXX
+func (e Entry) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"author_id"}, []string{"id"}, "author", mysql.WithDeleteForeignKeyOption(mysql.ForeignKeyOptionCascade)),
+	}
+}
+
+// Checks XXX
+func (e Entry) Checks() dialect.Checks {
+	return dialect.Checks{mysql.AddCheck("price_positive", "price >= 0")}
+}
+
+// TableOptions XXX
+func (e Entry) TableOptions() dialect.TableOptions {
+	return dialect.TableOptions{mysql.TableOptionRowFormat: "COMPRESSED"}
+}
+
+// Reply is not a table
+type Reply struct {
+	Body string
+}
+
+// Post is not a table, whose fields and methods are not the ones of tables
+type Post struct {
+	Params map[string]string
+	Ch     chan int
+	Reply  Reply
+}
+
+// Comment returns the first reply, which is not the comment of a table
+func (p Post) Comment() *Reply {
+	return &p.Reply
+}
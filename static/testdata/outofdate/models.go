@@ -0,0 +1,17 @@
+// Package outofdate is the models whose zz_ddl.go refers to a removed struct
+package outofdate
+
+import (
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+)
+
+// User XXX
+type User struct {
+	ID uint64
+}
+
+// PrimaryKey XXX
+func (u *User) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
@@ -6,22 +6,30 @@ import (
 
 // Table is mapping struct info
 type table struct {
-	name        string
-	primaryKey  dialect.PrimaryKey
-	foreignKeys dialect.ForeignKeys
-	columns     []dialect.Column
-	indexes     dialect.Indexes
-	dialect     dialect.Dialect
+	name         string
+	comment      string
+	primaryKey   dialect.PrimaryKey
+	foreignKeys  dialect.ForeignKeys
+	columns      []dialect.Column
+	indexes      dialect.Indexes
+	checks       dialect.Checks
+	options      dialect.TableOptions
+	partitioning dialect.Partitioning
+	dialect      dialect.Dialect
 }
 
-func newTable(name string, pk dialect.PrimaryKey, fks dialect.ForeignKeys, columns []dialect.Column, indexes dialect.Indexes, d dialect.Dialect) table {
+func newTable(name, comment string, pk dialect.PrimaryKey, fks dialect.ForeignKeys, columns []dialect.Column, indexes dialect.Indexes, checks dialect.Checks, options dialect.TableOptions, partitioning dialect.Partitioning, d dialect.Dialect) table {
 	return table{
-		name:        name,
-		primaryKey:  pk,
-		foreignKeys: fks,
-		columns:     columns,
-		indexes:     indexes,
-		dialect:     d,
+		name:         name,
+		comment:      comment,
+		primaryKey:   pk,
+		foreignKeys:  fks,
+		columns:      columns,
+		indexes:      indexes,
+		checks:       checks,
+		options:      options,
+		partitioning: partitioning,
+		dialect:      d,
 	}
 }
 
@@ -29,6 +37,10 @@ func (t table) Name() string {
 	return t.dialect.Quote(t.name)
 }
 
+func (t table) Comment() string {
+	return t.comment
+}
+
 func (t table) PrimaryKey() dialect.PrimaryKey {
 	return t.primaryKey
 }
@@ -45,6 +57,18 @@ func (t table) Indexes() dialect.Indexes {
 	return t.indexes
 }
 
+func (t table) Checks() dialect.Checks {
+	return t.checks
+}
+
+func (t table) TableOptions() dialect.TableOptions {
+	return t.options
+}
+
+func (t table) Partitioning() dialect.Partitioning {
+	return t.partitioning
+}
+
 func (t table) Dialect() dialect.Dialect {
 	return t.dialect
 }
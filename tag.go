@@ -0,0 +1,193 @@
+package ddlmaker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseTag parses ddl tag such as "null,size=100" into the map of spec name and value.
+//
+// Values are separated by commas, except for the commas in quotes or parentheses.
+// A value quoted by single quotes, such as comment='a, b', is unquoted, and a doubled quote or \' is a single quote in it.
+// A backslash escapes the next character, such as comment=a\,b.
+// Parenthesized values, such as type=enum('a','b'), are kept as they are.
+func parseTag(tag string) (map[string]string, error) {
+	specs, _, err := parseQuotedTag(tag)
+	return specs, err
+}
+
+// parseQuotedTag parses ddl tag as parseTag, and also returns the names of the specs whose values are quoted,
+// such as default='0001' which is a string but not a number.
+func parseQuotedTag(tag string) (map[string]string, map[string]bool, error) {
+	specs := make(map[string]string)
+	quoted := make(map[string]bool)
+	if strings.TrimSpace(tag) == "" {
+		return specs, quoted, nil
+	}
+
+	for i := 0; i <= len(tag); {
+		name, value, isQuoted, next, err := parseTagElem(tag, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("empty spec name at %d", i)
+		}
+		if _, ok := specs[name]; ok {
+			return nil, nil, fmt.Errorf("%s is duplicated", name)
+		}
+		specs[name] = value
+		if isQuoted {
+			quoted[name] = true
+		}
+		i = next + 1
+	}
+
+	return specs, quoted, nil
+}
+
+// parseTagElem parses name[=value] which starts at pos, and returns whether the value is quoted and the position of the comma or the end of tag.
+func parseTagElem(tag string, pos int) (string, string, bool, int, error) {
+	i := pos
+	for i < len(tag) && tag[i] != ',' && tag[i] != '=' {
+		i++
+	}
+	name := strings.TrimSpace(tag[pos:i])
+	if i >= len(tag) || tag[i] == ',' {
+		return name, "", false, i, nil
+	}
+
+	// skip '='
+	i++
+	for i < len(tag) && tag[i] == ' ' {
+		i++
+	}
+	if i < len(tag) && tag[i] == '\'' {
+		value, end, err := unquoteTagValue(tag, i)
+		if err != nil {
+			return "", "", false, 0, err
+		}
+		for end < len(tag) && tag[end] == ' ' {
+			end++
+		}
+		if end < len(tag) && tag[end] != ',' {
+			return "", "", false, 0, fmt.Errorf("unexpected %q after quoted value of %s at %d", tag[end], name, end)
+		}
+		return name, value, true, end, nil
+	}
+
+	var value strings.Builder
+	var depth int
+	for ; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case c == ',' && depth == 0:
+			return name, strings.TrimSpace(value.String()), false, i, nil
+		case c == '\\':
+			if i+1 >= len(tag) {
+				return "", "", false, 0, fmt.Errorf("unterminated escape of %s at %d", name, i)
+			}
+			i++
+			value.WriteByte(tag[i])
+		case c == '\'' && depth > 0:
+			// quoted string in parentheses is kept with its quotes
+			_, end, err := unquoteTagValue(tag, i)
+			if err != nil {
+				return "", "", false, 0, err
+			}
+			value.WriteString(tag[i:end])
+			i = end - 1
+		case c == '(':
+			depth++
+			value.WriteByte(c)
+		case c == ')':
+			if depth == 0 {
+				return "", "", false, 0, fmt.Errorf("unbalanced ) of %s at %d", name, i)
+			}
+			depth--
+			value.WriteByte(c)
+		default:
+			value.WriteByte(c)
+		}
+	}
+	if depth > 0 {
+		return "", "", false, 0, fmt.Errorf("unclosed ( of %s", name)
+	}
+
+	return name, strings.TrimSpace(value.String()), false, i, nil
+}
+
+// unquoteTagValue unquotes the value quoted by single quotes at pos, and returns the position next to the closing quote.
+func unquoteTagValue(tag string, pos int) (string, int, error) {
+	var value strings.Builder
+	for i := pos + 1; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case c == '\\' && i+1 < len(tag):
+			i++
+			value.WriteByte(tag[i])
+		case c == '\'' && i+1 < len(tag) && tag[i+1] == '\'':
+			i++
+			value.WriteByte(c)
+		case c == '\'':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated quote at %d", pos)
+}
+
+// parseValues parses the list of values such as ('a'|'b'|'c') or ('a','b','c').
+// Each value is quoted by single quotes, or is a bare word without separators.
+func parseValues(list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	if len(list) < 2 || list[0] != '(' || list[len(list)-1] != ')' {
+		return nil, fmt.Errorf("values %s is not enclosed in parentheses", list)
+	}
+	list = list[1 : len(list)-1]
+
+	var values []string
+	for i := 0; i < len(list); {
+		for i < len(list) && list[i] == ' ' {
+			i++
+		}
+
+		var value string
+		if i < len(list) && list[i] == '\'' {
+			v, end, err := unquoteTagValue(list, i)
+			if err != nil {
+				return nil, err
+			}
+			value, i = v, end
+		} else {
+			start := i
+			for i < len(list) && list[i] != '|' && list[i] != ',' {
+				i++
+			}
+			value = strings.TrimSpace(list[start:i])
+			if value == "" {
+				return nil, fmt.Errorf("empty value at %d", start)
+			}
+		}
+		values = append(values, value)
+
+		for i < len(list) && list[i] == ' ' {
+			i++
+		}
+		if i < len(list) {
+			if list[i] != '|' && list[i] != ',' {
+				return nil, fmt.Errorf("unexpected %q at %d", list[i], i)
+			}
+			i++
+			if i == len(list) {
+				return nil, fmt.Errorf("empty value at %d", i)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("values is empty")
+	}
+
+	return values, nil
+}
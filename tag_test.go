@@ -0,0 +1,118 @@
+package ddlmaker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag   string
+		specs map[string]string
+	}{
+		{"", map[string]string{}},
+		{"null,size=100", map[string]string{"null": "", "size": "100"}},
+		{" null , size = 100 ", map[string]string{"null": "", "size": "100"}},
+		{"default='a, b',null", map[string]string{"default": "a, b", "null": ""}},
+		{`comment='it''s \'quoted\''`, map[string]string{"comment": "it's 'quoted'"}},
+		{`comment=a\,b\\c`, map[string]string{"comment": `a,b\c`}},
+		{"type=enum('a','b,c'),null", map[string]string{"type": "enum('a','b,c')", "null": ""}},
+		{"default=concat(upper('a'), 'b')", map[string]string{"default": "concat(upper('a'), 'b')"}},
+		{"-", map[string]string{"-": ""}},
+	}
+
+	for _, c := range cases {
+		specs, err := parseTag(c.tag)
+		if err != nil {
+			t.Fatalf("error parse tag %s: %s", c.tag, err)
+		}
+		if !reflect.DeepEqual(specs, c.specs) {
+			t.Fatalf("error parse tag %s: %v", c.tag, specs)
+		}
+	}
+
+	errorCases := []struct {
+		tag string
+		err string
+	}{
+		{"comment='abc", "unterminated quote"},
+		{"comment='a'b", "unexpected 'b'"},
+		{"type=enum('a'", "unclosed ("},
+		{"type=enum)", "unbalanced )"},
+		{"null,,size=1", "empty spec name"},
+		{"null,", "empty spec name"},
+		{"=1", "empty spec name"},
+		{"size=1,size=2", "size is duplicated"},
+		{`comment=a\`, "unterminated escape"},
+	}
+
+	for _, c := range errorCases {
+		_, err := parseTag(c.tag)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Fatalf("error parse invalid tag %s: %v", c.tag, err)
+		}
+	}
+}
+
+func TestParseQuotedTag(t *testing.T) {
+	specs, quoted, err := parseQuotedTag("default='0001',comment=memo,type=enum('a')")
+	if err != nil {
+		t.Fatal("error parse quoted tag", err)
+	}
+	if specs["default"] != "0001" || !reflect.DeepEqual(quoted, map[string]bool{"default": true}) {
+		t.Fatalf("error parse quoted tag: %v %v", specs, quoted)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	cases := []struct {
+		list   string
+		values []string
+	}{
+		{"('a'|'b'|'c')", []string{"a", "b", "c"}},
+		{"( 'a', 'b,c' , 'it''s' )", []string{"a", "b,c", "it's"}},
+		{"(draft|published)", []string{"draft", "published"}},
+	}
+	for _, c := range cases {
+		values, err := parseValues(c.list)
+		if err != nil {
+			t.Fatalf("error parse values %s: %s", c.list, err)
+		}
+		if !reflect.DeepEqual(values, c.values) {
+			t.Fatalf("error parse values %s: %v", c.list, values)
+		}
+	}
+
+	for _, list := range []string{"'a'|'b'", "()", "('a'|)", "('a' 'b')", "('a|'b')"} {
+		if _, err := parseValues(list); err == nil {
+			t.Fatalf("error parse invalid values %s", list)
+		}
+	}
+}
+
+type T5 struct {
+	ID   uint64
+	Memo string `ddl:"comment='abc"`
+}
+
+func TestParseTagErrors(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T5{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs, ok := dm.parse().(Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "T5.Memo") || !strings.Contains(errs[0].Error(), "unterminated quote") {
+		t.Fatalf("error parse errors: %v", errs)
+	}
+}
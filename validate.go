@@ -0,0 +1,187 @@
+package ddlmaker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/pkg/errors"
+)
+
+// Validate checks that the keys and indexes of the added structs reference existing columns and tables.
+// It reports all problems at once as Errors.
+func (dm *DDLMaker) Validate() error {
+	if err := dm.parse(); err != nil {
+		return errors.Wrap(err, "error parse")
+	}
+
+	errs := validateTables(dm.Tables)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateTables(tables []dialect.Table) Errors {
+	var errs Errors
+
+	tableMap := make(map[string]dialect.Table, len(tables))
+	for _, t := range tables {
+		tableMap[t.Name()] = t
+	}
+	// the table of each index name for the dialects whose index names are unique in the schema
+	schemaIndexes := make(map[string]string)
+
+	for _, t := range tables {
+		columns := columnNames(t)
+		keyColumns := make(map[string]bool)
+
+		if pk := t.PrimaryKey(); pk != nil {
+			for _, c := range pk.Columns() {
+				keyColumns[c] = true
+				if !columns[c] {
+					errs = append(errs, fmt.Errorf("error %s: primary key column %s is not found", t.Name(), c))
+				}
+			}
+		}
+
+		indexNames := make(map[string]bool)
+		for _, idx := range t.Indexes() {
+			if indexNames[idx.Name()] {
+				errs = append(errs, fmt.Errorf("error %s: index %s is duplicated", t.Name(), idx.Name()))
+			}
+			indexNames[idx.Name()] = true
+			if d, ok := t.Dialect().(dialect.SchemaIndexName); ok && d.SchemaIndexName() {
+				if table, ok := schemaIndexes[idx.Name()]; ok && table != t.Name() {
+					errs = append(errs, fmt.Errorf("error %s: index %s is also used by table %s", t.Name(), idx.Name(), table))
+				} else {
+					schemaIndexes[idx.Name()] = t.Name()
+				}
+			}
+
+			for _, c := range idx.Columns() {
+				keyColumns[c] = true
+				if !columns[c] {
+					errs = append(errs, fmt.Errorf("error %s: index %s column %s is not found", t.Name(), idx.Name(), c))
+				}
+			}
+		}
+
+		for _, fk := range t.ForeignKeys() {
+			for _, c := range fk.ForeignColumns() {
+				if !columns[c] {
+					errs = append(errs, fmt.Errorf("error %s: foreign key column %s is not found", t.Name(), c))
+				}
+			}
+
+			ref, ok := tableMap[t.Dialect().Quote(fk.ReferenceTableName())]
+			if !ok {
+				errs = append(errs, fmt.Errorf("error %s: foreign key reference table %s is not found", t.Name(), fk.ReferenceTableName()))
+				continue
+			}
+			refColumns := columnNames(ref)
+			for _, c := range fk.ReferenceColumns() {
+				if !refColumns[c] {
+					errs = append(errs, fmt.Errorf("error %s: foreign key reference column %s.%s is not found", t.Name(), fk.ReferenceTableName(), c))
+				}
+			}
+		}
+
+		errs = append(errs, validatePartitioning(t, columns, tableMap)...)
+
+		for _, c := range t.Columns() {
+			if ac, ok := c.(dialect.AutoIncrementColumn); ok && ac.AutoIncrement() && !keyColumns[c.Name()] {
+				errs = append(errs, fmt.Errorf("error %s: auto increment column %s is not a part of any key", t.Name(), c.Name()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validatePartitioning checks that the partitioning columns are a part of the primary key and every unique index,
+// and the partitioned table has no foreign keys, which MySQL does not support.
+func validatePartitioning(t dialect.Table, columns map[string]bool, tableMap map[string]dialect.Table) Errors {
+	var errs Errors
+
+	p := t.Partitioning()
+	if p == nil {
+		return nil
+	}
+
+	if len(t.ForeignKeys()) > 0 {
+		errs = append(errs, fmt.Errorf("error %s: partitioned table can not have foreign keys", t.Name()))
+	}
+	for _, name := range sortedTableNames(tableMap) {
+		for _, fk := range tableMap[name].ForeignKeys() {
+			if t.Dialect().Quote(fk.ReferenceTableName()) == t.Name() {
+				errs = append(errs, fmt.Errorf("error %s: partitioned table is referenced by foreign key of %s", t.Name(), name))
+			}
+		}
+	}
+
+	keys := make(map[string][]string)
+	if pk := t.PrimaryKey(); pk != nil {
+		keys["primary key"] = pk.Columns()
+	}
+	for _, idx := range t.Indexes() {
+		if strings.HasPrefix(idx.ToSQL(), "UNIQUE") {
+			keys["unique index "+idx.Name()] = idx.Columns()
+		}
+	}
+
+	for _, c := range p.Columns() {
+		if !columns[c] {
+			errs = append(errs, fmt.Errorf("error %s: partitioning column %s is not found", t.Name(), c))
+			continue
+		}
+		for _, name := range sortedKeys(keys) {
+			if !containsString(keys[name], c) {
+				errs = append(errs, fmt.Errorf("error %s: partitioning column %s is not a part of %s", t.Name(), c, name))
+			}
+		}
+	}
+
+	return errs
+}
+
+func sortedTableNames(m map[string]dialect.Table) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func columnNames(t dialect.Table) map[string]bool {
+	names := make(map[string]bool, len(t.Columns()))
+	for _, c := range t.Columns() {
+		names[c.Name()] = true
+	}
+
+	return names
+}
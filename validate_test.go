@@ -0,0 +1,214 @@
+package ddlmaker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kayac/ddl-maker/dialect"
+	"github.com/kayac/ddl-maker/dialect/mysql"
+	"github.com/kayac/ddl-maker/dialect/postgres"
+)
+
+type ValidateEntry struct {
+	ID    uint64 `ddl:"auto"`
+	Seq   uint64 `ddl:"auto"`
+	Title string
+}
+
+func (e ValidateEntry) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (e ValidateEntry) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		mysql.AddIndex("title_idx", "title"),
+		mysql.AddUniqueIndex("title_idx", "title"),
+	}
+}
+
+func (e ValidateEntry) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"id"}, []string{"entry_id"}, "test1"),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(T1{}, ValidateEntry{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.Validate()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("error validate errors: %v", err)
+	}
+
+	expected := []string{
+		"`test1`: index token_idx column token is not found",
+		"`test1`: foreign key column player_id is not found",
+		"`test1`: foreign key reference table player is not found",
+		"`validate_entry`: index title_idx is duplicated",
+		"`validate_entry`: foreign key reference column test1.entry_id is not found",
+		"`validate_entry`: auto increment column seq is not a part of any key",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("error validate errors: %v", errs)
+	}
+	for i, e := range expected {
+		if !strings.Contains(errs[i].Error(), e) {
+			t.Fatalf("error validate errors[%d]: %s, expected: %s", i, errs[i], e)
+		}
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "postgres"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(SortComment{}, SortEntry{}, SortAuthor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.Validate(); err != nil {
+		t.Fatalf("error validate valid tables: %v", err)
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "postgres"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(ValidateUser{}, ValidateGroup{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.Validate()
+	if errs, ok := err.(Errors); !ok || len(errs) != 1 || !strings.Contains(errs[0].Error(), `"validate_group": index idx_name is also used by table "validate_user"`) {
+		t.Fatalf("error validate index names in the schema: %v", err)
+	}
+}
+
+type ValidateUser struct {
+	ID   uint64
+	Name string
+}
+
+func (u ValidateUser) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (u ValidateUser) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		postgres.AddIndex("idx_name", "name"),
+	}
+}
+
+type ValidateGroup struct {
+	ID   uint64
+	Name string
+}
+
+func (g ValidateGroup) PrimaryKey() dialect.PrimaryKey {
+	return postgres.AddPrimaryKey("id")
+}
+
+func (g ValidateGroup) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		postgres.AddIndex("idx_name", "name"),
+	}
+}
+
+type PartitionLog struct {
+	ID        uint64 `ddl:"auto"`
+	Token     string `ddl:"size=36"`
+	CreatedAt time.Time
+}
+
+func (l PartitionLog) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id", "created_at")
+}
+
+func (l PartitionLog) Indexes() dialect.Indexes {
+	return dialect.Indexes{
+		mysql.AddUniqueIndex("token_idx", "token"),
+		mysql.AddIndex("created_at_idx", "created_at"),
+	}
+}
+
+func (l PartitionLog) Partitioning() dialect.Partitioning {
+	return mysql.PartitionByRange("TO_DAYS(created_at)",
+		mysql.AddPartition("p2023", "TO_DAYS('2024-01-01')"),
+		mysql.AddPartition("pmax", "MAXVALUE"),
+	)
+}
+
+type PartitionLogRef struct {
+	ID    uint64
+	LogID uint64
+}
+
+func (r PartitionLogRef) PrimaryKey() dialect.PrimaryKey {
+	return mysql.AddPrimaryKey("id")
+}
+
+func (r PartitionLogRef) ForeignKeys() dialect.ForeignKeys {
+	return dialect.ForeignKeys{
+		mysql.AddForeignKey([]string{"log_id"}, []string{"id"}, "partition_log"),
+	}
+}
+
+func TestValidatePartitioning(t *testing.T) {
+	dm, err := New(Config{
+		DB: DBConfig{Driver: "mysql"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(PartitionLog{}, PartitionLogRef{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dm.Validate()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("error validate errors: %v", err)
+	}
+
+	expected := []string{
+		"`partition_log`: partitioned table is referenced by foreign key of `partition_log_ref`",
+		"`partition_log`: partitioning column created_at is not a part of unique index token_idx",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("error validate errors: %v", errs)
+	}
+	for i, e := range expected {
+		if !strings.Contains(errs[i].Error(), e) {
+			t.Fatalf("error validate errors[%d]: %s, expected: %s", i, errs[i], e)
+		}
+	}
+
+	dm, err = New(Config{
+		DB: DBConfig{Driver: "postgres"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dm.AddStruct(PartitionLog{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.Validate(); err == nil {
+		t.Fatal("error validate partitioning of postgres")
+	}
+}